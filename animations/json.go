@@ -0,0 +1,100 @@
+package animations
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the animation in the format of the API, as produced by ToMap.
+func (a BaseAnimation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.ToMap())
+}
+
+// UnmarshalJSON decodes an animation of any type, keeping its properties as a map.
+func (a *BaseAnimation) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	animationType, _ := raw["type"].(string)
+	delete(raw, "type")
+	*a = BaseAnimation{Type: animationType, Properties: raw}
+	return nil
+}
+
+func (f *Fade) UnmarshalJSON(data []byte) error {
+	var props FadeProperties
+	if err := unmarshalAnimation(data, "fade", &props); err != nil {
+		return err
+	}
+	*f = *NewFade(props)
+	return nil
+}
+
+func (s *Slide) UnmarshalJSON(data []byte) error {
+	var props SlideProperties
+	if err := unmarshalAnimation(data, "slide", &props); err != nil {
+		return err
+	}
+	*s = *NewSlide(props)
+	return nil
+}
+
+func (s *Scale) UnmarshalJSON(data []byte) error {
+	var props ScaleProperties
+	if err := unmarshalAnimation(data, "scale", &props); err != nil {
+		return err
+	}
+	*s = *NewScale(props)
+	return nil
+}
+
+func (s *Spin) UnmarshalJSON(data []byte) error {
+	var props SpinProperties
+	if err := unmarshalAnimation(data, "spin", &props); err != nil {
+		return err
+	}
+	*s = *NewSpin(props)
+	return nil
+}
+
+func (t *TextAppear) UnmarshalJSON(data []byte) error {
+	var props TextAppearProperties
+	if err := unmarshalAnimation(data, "text-appear", &props); err != nil {
+		return err
+	}
+	*t = *NewTextAppear(props)
+	return nil
+}
+
+func (t *TextSlide) UnmarshalJSON(data []byte) error {
+	var props TextSlideProperties
+	if err := unmarshalAnimation(data, "text-slide", &props); err != nil {
+		return err
+	}
+	*t = *NewTextSlide(props)
+	return nil
+}
+
+func (t *TextTypewriter) UnmarshalJSON(data []byte) error {
+	var props TextTypewriterProperties
+	if err := unmarshalAnimation(data, "text-typewriter", &props); err != nil {
+		return err
+	}
+	*t = *NewTextTypewriter(props)
+	return nil
+}
+
+// unmarshalAnimation decodes the properties of an animation of the given type.
+func unmarshalAnimation(data []byte, animationType string, props interface{}) error {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	if header.Type != animationType {
+		return fmt.Errorf("cannot unmarshal %q animation into %s animation", header.Type, animationType)
+	}
+	return json.Unmarshal(data, props)
+}
@@ -0,0 +1,341 @@
+package creatomate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultBatchConcurrency = 4
+
+// Limiter throttles requests. It is satisfied by *RateLimiter and by golang.org/x/time/rate.Limiter.
+type Limiter interface {
+	// Wait blocks until a request may be made or the context is done.
+	Wait(ctx context.Context) error
+}
+
+type BatchOptions struct {
+	// The maximum number of items rendered at the same time. Defaults to 4.
+	Concurrency int
+
+	// Waited on before every StartRender, so that it can be shared with other batches.
+	Limiter Limiter
+
+	// Identifies every item across runs, one key per item. Defaults to the item's index.
+	Keys []string
+
+	// Records progress so that a batch can be resumed after a crash without starting
+	// renders twice. Items that succeeded in an earlier run are not rendered again.
+	Checkpoint BatchCheckpoint
+
+	// The timeout for rendering a single item. Defaults to the client's render timeout.
+	Timeout time.Duration
+
+	// Called whenever an item has finished, from the goroutine that rendered it.
+	OnResult func(BatchResult)
+}
+
+type BatchResult struct {
+	// The key and index of the item.
+	Key   string
+	Index int
+
+	// The renders of the item, in their last known state.
+	Renders []Render
+
+	// The error that prevented the item from finishing, if any.
+	Err error
+
+	// Set when the result was loaded from the checkpoint of an earlier run.
+	Resumed bool
+}
+
+// Succeeded reports whether all renders of the item succeeded.
+func (r BatchResult) Succeeded() bool {
+	if r.Err != nil || len(r.Renders) == 0 {
+		return false
+	}
+	for _, render := range r.Renders {
+		if render.Status != RenderStatusSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+// BatchRecord is the progress of a single batch item, as stored in a BatchCheckpoint.
+type BatchRecord struct {
+	Key     string   `json:"key"`
+	Renders []Render `json:"renders,omitempty"`
+	Done    bool     `json:"done"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// BatchCheckpoint stores the progress of batch items. Implementations must be safe for
+// concurrent use.
+type BatchCheckpoint interface {
+	// Load returns the record of the item with the given key, or nil if there is none.
+	Load(key string) (*BatchRecord, error)
+
+	// Save stores the record, replacing any earlier record with the same key.
+	Save(record BatchRecord) error
+}
+
+// RenderBatch renders every item with bounded concurrency. A failing item doesn't stop the
+// others; the results are returned in the order of the items.
+func (c *Client) RenderBatch(ctx context.Context, items []RenderOptions, options BatchOptions) ([]BatchResult, error) {
+	keys := options.Keys
+	if keys == nil {
+		keys = make([]string, len(items))
+		for i := range items {
+			keys[i] = strconv.Itoa(i)
+		}
+	}
+	if len(keys) != len(items) {
+		return nil, fmt.Errorf("batch has %d items but %d keys", len(items), len(keys))
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate batch key %q", key)
+		}
+		seen[key] = true
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	results := make([]BatchResult, len(items))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(items); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = c.renderBatchItem(ctx, i, keys[i], items[i], options)
+				if options.OnResult != nil {
+					options.OnResult(results[i])
+				}
+			}
+		}()
+	}
+
+	for i := range items {
+		if ctx.Err() != nil {
+			results[i] = BatchResult{Key: keys[i], Index: i, Err: ctx.Err()}
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+// renderBatchItem renders a single item, resuming from its checkpoint record if there is one.
+func (c *Client) renderBatchItem(ctx context.Context, index int, key string, item RenderOptions, options BatchOptions) BatchResult {
+	result := BatchResult{Key: key, Index: index}
+
+	var record *BatchRecord
+	if options.Checkpoint != nil {
+		var err error
+		if record, err = options.Checkpoint.Load(key); err != nil {
+			result.Err = fmt.Errorf("failed to load checkpoint: %w", err)
+			return result
+		}
+	}
+
+	var renders []Render
+	var startedAt time.Time
+	switch {
+	case record != nil && record.Done && record.Error == "":
+		result.Renders = record.Renders
+		result.Resumed = true
+		return result
+	case record != nil && !record.Done && len(record.Renders) > 0:
+		// The renders were started by an earlier run, so only wait for them
+		renders = record.Renders
+		result.Resumed = true
+	default:
+		if options.Limiter != nil {
+			if err := options.Limiter.Wait(ctx); err != nil {
+				result.Err = err
+				return result
+			}
+		}
+
+		var err error
+		startedAt = time.Now()
+		if renders, err = c.StartRender(ctx, item); err != nil {
+			result.Err = err
+			c.saveBatchRecord(options.Checkpoint, BatchRecord{Key: key, Done: true, Error: err.Error()})
+			return result
+		}
+		if err := c.saveBatchRecord(options.Checkpoint, BatchRecord{Key: key, Renders: renders}); err != nil {
+			result.Renders = renders
+			result.Err = err
+			return result
+		}
+	}
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = c.renderTimeout
+	}
+
+	finished, err := c.awaitRenders(ctx, renders, timeout, startedAt, item.PerRenderTimeout)
+	if err != nil {
+		result.Renders = mergeRenders(renders, finished)
+		result.Err = err
+		if item.CancelOnAbandon && isAbandoned(ctx, err) {
+			if cancelErr := c.cancelUnfinished(ctx, renders, finished); cancelErr != nil {
+				result.Err = errors.Join(err, cancelErr)
+			}
+			// The renders won't finish, so a later run starts the item again
+			c.saveBatchRecord(options.Checkpoint, BatchRecord{Key: key, Renders: result.Renders, Done: true, Error: result.Err.Error()})
+			return result
+		}
+		// Keep the renders unfinished in the checkpoint, so that a later run waits for them
+		c.saveBatchRecord(options.Checkpoint, BatchRecord{Key: key, Renders: result.Renders, Error: err.Error()})
+		return result
+	}
+
+	result.Renders = finished
+	done := BatchRecord{Key: key, Renders: finished, Done: true}
+	if !result.Succeeded() {
+		// Failed items are started again by a later run
+		done.Error = renderFailure(finished)
+	}
+	if err := c.saveBatchRecord(options.Checkpoint, done); err != nil {
+		result.Err = err
+	}
+	return result
+}
+
+// renderFailure describes the first render that didn't succeed.
+func renderFailure(renders []Render) string {
+	for _, render := range renders {
+		if render.Status != RenderStatusSucceeded {
+			return fmt.Sprintf("render %s %s: %s", render.ID, render.Status, render.ErrorMessage)
+		}
+	}
+	return "no renders were started"
+}
+
+// mergeRenders returns renders with every render replaced by its updated state, if any.
+func mergeRenders(renders, updated []Render) []Render {
+	merged := make([]Render, len(renders))
+	copy(merged, renders)
+	for i := range merged {
+		for _, render := range updated {
+			if render.ID == merged[i].ID {
+				merged[i] = render
+			}
+		}
+	}
+	return merged
+}
+
+func (c *Client) saveBatchRecord(checkpoint BatchCheckpoint, record BatchRecord) error {
+	if checkpoint == nil {
+		return nil
+	}
+	if err := checkpoint.Save(record); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// MemoryCheckpoint is a BatchCheckpoint that keeps records in memory. It allows resuming
+// a batch within the same process, e.g. after the context was canceled.
+type MemoryCheckpoint struct {
+	mu      sync.Mutex
+	records map[string]BatchRecord
+}
+
+func NewMemoryCheckpoint() *MemoryCheckpoint {
+	return &MemoryCheckpoint{records: make(map[string]BatchRecord)}
+}
+
+func (m *MemoryCheckpoint) Load(key string) (*BatchRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *MemoryCheckpoint) Save(record BatchRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[record.Key] = record
+	return nil
+}
+
+// FileCheckpoint is a BatchCheckpoint that stores records in a JSON file, which is rewritten
+// atomically on every save.
+type FileCheckpoint struct {
+	path   string
+	memory *MemoryCheckpoint
+}
+
+// NewFileCheckpoint opens the checkpoint file at path, which is created on the first save.
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	checkpoint := &FileCheckpoint{path: path, memory: NewMemoryCheckpoint()}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &checkpoint.memory.records); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %w", path, err)
+	}
+	return checkpoint, nil
+}
+
+func (f *FileCheckpoint) Load(key string) (*BatchRecord, error) {
+	return f.memory.Load(key)
+}
+
+func (f *FileCheckpoint) Save(record BatchRecord) error {
+	f.memory.mu.Lock()
+	defer f.memory.mu.Unlock()
+
+	f.memory.records[record.Key] = record
+	data, err := json.MarshalIndent(f.memory.records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
@@ -0,0 +1,158 @@
+package creatomate
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CanonicalJSON encodes the source in a canonical form of the JSON produced by ToMap, so that
+// semantically equal sources encode to identical bytes, e.g. for hashing or golden files:
+//
+//   - object keys are sorted and HTML characters aren't escaped;
+//   - numbers are normalized, so 30, 30.0 and int64(30) all encode as 30 and -0 as 0;
+//   - null values, empty strings, empty objects and empty arrays are left out of objects;
+//   - the animations of an element are ordered by time ("start", then times in seconds or
+//     percentages, then "end"), and by their canonical JSON when the times are equal. Times
+//     that can't be compared, such as unparsable ones, keep their position.
+//
+// The order of elements and keyframes is kept, as it affects the render. Numbers are
+// represented as float64, so integers beyond 2^53 may lose precision.
+func (s *Source) CanonicalJSON() ([]byte, error) {
+	data, err := json.Marshal(s.ToMap())
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	value, _ = canonicalValue(value, "")
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// canonicalValue normalizes a decoded JSON value stored under key. It reports false for
+// values that are left out of objects.
+func canonicalValue(value interface{}, key string) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return v, v != ""
+	case float64:
+		if v == 0 {
+			return float64(0), true
+		}
+		return v, true
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if item, ok := canonicalValue(item, k); ok {
+				result[k] = item
+			}
+		}
+		return result, len(result) > 0
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			// Items are kept even when empty, as their position matters
+			result[i], _ = canonicalValue(item, "")
+		}
+		if key == "animations" {
+			sortAnimations(result)
+		}
+		return result, len(result) > 0
+	default:
+		return v, true
+	}
+}
+
+// sortAnimations orders animations by their time, and by their JSON for equal times. Times in
+// seconds and percentages of the element's duration can't be compared with each other, so when
+// both are used, the percentages keep their position, as do times that can't be parsed.
+func sortAnimations(list []interface{}) {
+	times := make([]animationTime, len(list))
+	hasSeconds := false
+	for i, item := range list {
+		data, _ := item.(map[string]interface{})
+		times[i] = parseAnimationTime(data["time"])
+		hasSeconds = hasSeconds || times[i].ok && times[i].rank == 1 && !times[i].percentage
+	}
+
+	// Only the animations whose times can be ranked are sorted, among the positions they hold
+	var positions []int
+	for i, time := range times {
+		if !time.ok || time.percentage && hasSeconds {
+			continue
+		}
+		positions = append(positions, i)
+	}
+	keys := make(map[int]string, len(positions))
+	for _, i := range positions {
+		data, _ := json.Marshal(list[i])
+		keys[i] = string(data)
+	}
+
+	indexes := append([]int(nil), positions...)
+	sort.SliceStable(indexes, func(a, b int) bool {
+		timeA, timeB := times[indexes[a]], times[indexes[b]]
+		if timeA.rank != timeB.rank {
+			return timeA.rank < timeB.rank
+		}
+		if timeA.value != timeB.value {
+			return timeA.value < timeB.value
+		}
+		return keys[indexes[a]] < keys[indexes[b]]
+	})
+
+	sorted := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		sorted[i] = list[index]
+	}
+	for i, position := range positions {
+		list[position] = sorted[i]
+	}
+}
+
+// animationTime is the time of an animation: "start" has rank 0, times in seconds or
+// percentages rank 1 and "end" ranks 2.
+type animationTime struct {
+	rank       int
+	value      float64
+	percentage bool
+	ok         bool
+}
+
+// parseAnimationTime parses a time such as 2, "2", "2 s", "50%", "start" or "end". A missing
+// time counts as 0 seconds.
+func parseAnimationTime(value interface{}) animationTime {
+	switch time := value.(type) {
+	case nil:
+		return animationTime{rank: 1, ok: true}
+	case float64:
+		return animationTime{rank: 1, value: time, ok: true}
+	case string:
+		time = strings.TrimSpace(time)
+		switch time {
+		case "start":
+			return animationTime{rank: 0, ok: true}
+		case "end":
+			return animationTime{rank: 2, ok: true}
+		}
+		percentage := strings.HasSuffix(time, "%")
+		number := strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(time, "%"), "s"))
+		if n, err := strconv.ParseFloat(number, 64); err == nil {
+			return animationTime{rank: 1, value: n, percentage: percentage, ok: true}
+		}
+	}
+	return animationTime{}
+}
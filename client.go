@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -15,70 +17,220 @@ const (
 	BaseURL       = "https://api.creatomate.com/v1"
 )
 
+// DefaultRenderTimeout is how long Render waits when called with a zero timeout, unless
+// changed with WithRenderTimeout.
+const DefaultRenderTimeout = 15 * time.Minute
+
+const defaultMaxRenderTimeout = 60 * time.Minute
+
+// The time allowed for canceling abandoned renders.
+const cancelTimeout = 30 * time.Second
+
 type Client struct {
-	apiKey     string
-	httpClient *http.Client
+	apiKey           string
+	baseURL          string
+	userAgentSuffix  string
+	renderTimeout    time.Duration
+	maxRenderTimeout time.Duration
+	retryPolicy      RetryPolicy
+	webhookSecret    []byte
+	pollStrategy     PollStrategy
+	pollBudget       *roundBudget
+	rateLimiters     map[string]*RateLimiter
+	interceptors     []Interceptor
+	telemetry        Telemetry
+	validateSources  bool
+	transport        http.RoundTripper
+	httpClient       *http.Client
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
-		apiKey: apiKey,
+func NewClient(apiKey string, options ...Option) *Client {
+	c := &Client{
+		apiKey:           apiKey,
+		baseURL:          BaseURL,
+		renderTimeout:    DefaultRenderTimeout,
+		maxRenderTimeout: defaultMaxRenderTimeout,
+		pollStrategy:     FixedInterval(defaultPollInterval),
+		telemetry:        NoopTelemetry{},
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+	for _, option := range options {
+		option(c)
+	}
+	// The transport is set last, so that WithHTTPClient doesn't replace it
+	if c.transport != nil {
+		httpClient := *c.httpClient
+		httpClient.Transport = c.transport
+		c.httpClient = &httpClient
+	}
+	return c
 }
 
-// Render starts a new render and awaits its completion.
+// Render starts a new render and awaits its completion. The timeout is counted from when the
+// render was started and is limited to 60 minutes unless changed with WithMaxRenderTimeout.
+// If it expires, a *TimeoutError holding the unfinished renders is returned along with the
+// renders that did finish.
 func (c *Client) Render(ctx context.Context, options RenderOptions, timeout time.Duration) ([]Render, error) {
 	if timeout == 0 {
-		timeout = 15 * time.Minute
+		timeout = c.renderTimeout
 	}
-	if timeout > 60*time.Minute {
-		timeout = 60 * time.Minute
+	if c.maxRenderTimeout > 0 && timeout > c.maxRenderTimeout {
+		timeout = c.maxRenderTimeout
 	}
 
+	startedAt := time.Now()
+	ctx, span := c.telemetry.StartSpan(ctx, SpanRender)
+
 	renders, err := c.StartRender(ctx, options)
+	if err == nil {
+		started := renders
+		renders, err = c.awaitRenders(ctx, renders, timeout, startedAt, options.PerRenderTimeout)
+		if err != nil && options.CancelOnAbandon && isAbandoned(ctx, err) {
+			if cancelErr := c.cancelUnfinished(ctx, started, renders); cancelErr != nil {
+				err = errors.Join(err, cancelErr)
+			}
+		}
+	}
+
+	c.telemetry.Observe(MetricRenderDuration, time.Since(startedAt).Seconds())
 	if err != nil {
-		return nil, err
+		c.telemetry.Count(MetricFailures, 1, Attribute{Key: "error_type", Value: errorType(err)})
 	}
+	for _, render := range renders {
+		span.SetAttributes(Attribute{Key: "render_id", Value: render.ID}, Attribute{Key: "status", Value: string(render.Status)})
+		if render.Status == RenderStatusFailed {
+			c.telemetry.Count(MetricFailures, 1, Attribute{Key: "error_type", Value: "render_failed"})
+		}
+	}
+	span.End(err)
+
+	return renders, err
+}
+
+// awaitRenders watches the renders until they are all finished or have passed their deadline,
+// which is the timeout or the shorter time returned by perRender, if it isn't nil. startedAt is
+// when the renders were started, or zero if unknown.
+func (c *Client) awaitRenders(ctx context.Context, renders []Render, timeout time.Duration, startedAt time.Time, perRender func(Render) time.Duration) ([]Render, error) {
+	now := time.Now()
+	timings := newRenderTimings(c.telemetry, renders, startedAt, now)
 
-	startTime := time.Now()
-	unfinishedRenders := make([]Render, len(renders))
-	copy(unfinishedRenders, renders)
 	finishedRenders := make([]Render, 0, len(renders))
+	pending := make(map[string]Render, len(renders))
+	deadlines := make(map[string]time.Time, len(renders))
+	for _, render := range renders {
+		if render.Status != "" && !render.Status.InProgress() {
+			finishedRenders = append(finishedRenders, render)
+			continue
+		}
+		pending[render.ID] = render
+		deadlines[render.ID] = now.Add(timeout)
+		if perRender != nil {
+			if d := perRender(render); d > 0 && d < timeout {
+				deadlines[render.ID] = now.Add(d)
+			}
+		}
+	}
 
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	// Renders past their deadline are given up on, so they must not use up the poll budget
+	abandoned := newRenderSet()
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := c.watch(watchCtx, renders, abandoned)
 
-	for len(unfinishedRenders) > 0 {
-		select {
-		case <-ctx.Done():
-			return finishedRenders, ctx.Err()
-		case <-ticker.C:
-			if time.Since(startTime) >= timeout {
-				return finishedRenders, NewTimeoutError()
+	// nextDeadline returns the earliest deadline of the pending renders
+	nextDeadline := func() time.Time {
+		var next time.Time
+		for id := range pending {
+			if next.IsZero() || deadlines[id].Before(next) {
+				next = deadlines[id]
 			}
+		}
+		return next
+	}
 
-			for i := len(unfinishedRenders) - 1; i >= 0; i-- {
-				render := unfinishedRenders[i]
-				updatedRender, err := c.FetchRender(ctx, render.ID)
-				if err != nil {
-					return finishedRenders, err
-				}
+	timer := time.NewTimer(time.Until(nextDeadline()))
+	defer timer.Stop()
 
-				if !isRenderInProgress(updatedRender.Status) {
-					unfinishedRenders = append(unfinishedRenders[:i], unfinishedRenders[i+1:]...)
-					finishedRenders = append(finishedRenders, *updatedRender)
+	var expired []Render
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return finishedRenders, ctx.Err()
+		case now := <-timer.C:
+			// Give up on the renders past their deadline, in the order they were started
+			for _, render := range renders {
+				if latest, ok := pending[render.ID]; ok && !now.Before(deadlines[render.ID]) {
+					expired = append(expired, latest)
+					delete(pending, render.ID)
+					abandoned.add(render.ID)
 				}
 			}
+			if len(pending) > 0 {
+				timer.Reset(time.Until(nextDeadline()))
+			}
+		case event, ok := <-events:
+			if !ok {
+				return finishedRenders, ctx.Err()
+			}
+			if event.Err != nil {
+				return finishedRenders, event.Err
+			}
+			if _, ok := pending[event.Render.ID]; !ok {
+				continue
+			}
+			timings.observe(event.Render, event.PreviousStatus, event.Time)
+			if event.Done() {
+				delete(pending, event.Render.ID)
+				finishedRenders = append(finishedRenders, event.Render)
+			} else {
+				pending[event.Render.ID] = event.Render
+			}
 		}
 	}
 
+	if len(expired) > 0 {
+		return finishedRenders, NewTimeoutErrorWithRenders(expired)
+	}
 	return finishedRenders, nil
 }
 
-// StartRender starts a render, but doesn't wait for it to finish.
+// isAbandoned reports whether awaiting renders stopped because of err without them failing.
+func isAbandoned(ctx context.Context, err error) bool {
+	var timeoutError *TimeoutError
+	return ctx.Err() != nil || errors.As(err, &timeoutError)
+}
+
+// cancelUnfinished cancels the started renders that aren't among the finished ones. It keeps
+// going when the context is done, since that is usually why the renders are abandoned.
+func (c *Client) cancelUnfinished(ctx context.Context, started, finished []Render) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, render := range started {
+		if containsRender(finished, render.ID) {
+			continue
+		}
+		if _, err := c.CancelRender(ctx, render.ID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to cancel render %s: %w", render.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func containsRender(renders []Render, id string) bool {
+	for _, render := range renders {
+		if render.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// StartRender starts a render, but doesn't wait for it to finish. With WithSourceValidation,
+// the source is validated first and the validation errors are returned without a request.
 func (c *Client) StartRender(ctx context.Context, options RenderOptions) ([]Render, error) {
 	payload := transformObjectKeysToSnake(options)
 	
@@ -88,10 +240,35 @@ func (c *Client) StartRender(ctx context.Context, options RenderOptions) ([]Rend
 		} else {
 			payload["source"] = options.Source
 		}
+		if c.validateSources {
+			source, err := sourceMap(options.Source)
+			if err != nil {
+				return nil, err
+			}
+			if err := validateSourceMap(source, options.Modifications); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	signed := len(c.webhookSecret) > 0 && options.WebhookURL != ""
+	if signed {
+		token, err := SignMetadata(c.webhookSecret, options.Metadata, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		payload["metadata"] = token
 	}
 
 	var renders []Render
 	err := c.httpRequest(ctx, "POST", "/renders", payload, &renders)
+
+	// Hide the signed token from the caller
+	if signed {
+		for i := range renders {
+			renders[i].Metadata = options.Metadata
+		}
+	}
 	return renders, err
 }
 
@@ -105,38 +282,118 @@ func (c *Client) FetchRender(ctx context.Context, id string) (*Render, error) {
 	return &render, nil
 }
 
+// CancelRender cancels a render that hasn't finished yet and returns its updated state.
+func (c *Client) CancelRender(ctx context.Context, id string) (*Render, error) {
+	var render Render
+	err := c.httpRequest(ctx, "POST", fmt.Sprintf("/renders/%s/cancel", url.PathEscape(id)), nil, &render)
+	if err != nil {
+		return nil, err
+	}
+	return &render, nil
+}
+
+// DeleteRender deletes a render along with its output files.
+func (c *Client) DeleteRender(ctx context.Context, id string) error {
+	return c.httpRequest(ctx, "DELETE", fmt.Sprintf("/renders/%s", url.PathEscape(id)), nil, nil)
+}
+
 func (c *Client) httpRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	var bodyReader io.Reader
+	var bodyBytes []byte
 	if body != nil {
-		bodyBytes, err := json.Marshal(body)
+		var err error
+		bodyBytes, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.doRequest(ctx, method, path, bodyBytes, result)
+		if err == nil || attempt >= c.retryPolicy.maxAttempts(method, err) || ctx.Err() != nil || !IsRetryable(err) {
+			return err
+		}
+
+		retryAfter := time.Duration(-1)
+		var apiError *CreatomateError
+		if errors.As(err, &apiError) {
+			retryAfter = parseRetryAfter(apiError.Header.Get("Retry-After"), time.Now())
+		}
+
+		delay := c.retryPolicy.backoff(attempt, retryAfter)
+		if c.retryPolicy.OnRetry != nil {
+			c.retryPolicy.OnRetry(RetryAttempt{
+				Method:  method,
+				Path:    path,
+				Attempt: attempt,
+				Err:     err,
+				Delay:   delay,
+			})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// doRequest performs a single attempt of an API request.
+func (c *Client) doRequest(ctx context.Context, method, path string, bodyBytes []byte, result interface{}) error {
+	var bodyReader io.Reader
+	if bodyBytes != nil {
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, BaseURL+path, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("User-Agent", "Creatomate-Go/"+ClientVersion)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Content-Type", "application/json")
 
+	start := time.Now()
+	limiter := c.rateLimiter(method, path)
+	if limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			c.onRequestError(req, err, time.Since(start))
+			return err
+		}
+	}
+
+	if err := c.beforeRequest(req); err != nil {
+		c.onRequestError(req, err, time.Since(start))
+		return err
+	}
+
+	start = time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return NewConnectionError()
+		connectionError := newConnectionErrorWithCause(err)
+		c.onRequestError(req, connectionError, time.Since(start))
+		return connectionError
 	}
 	defer resp.Body.Close()
 
+	if limiter != nil {
+		limiter.observe(resp, time.Now())
+	}
+
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		connectionError := newConnectionErrorWithCause(err)
+		connectionError.setResponse(resp, nil)
+		c.onRequestError(req, connectionError, time.Since(start))
+		return connectionError
 	}
+	c.afterResponse(req, resp, respBody, time.Since(start))
 
 	if resp.StatusCode >= 400 {
-		return c.transformError(resp.StatusCode, respBody)
+		return c.transformError(resp, respBody)
 	}
 
 	if result != nil && len(respBody) > 0 {
@@ -148,31 +405,42 @@ func (c *Client) httpRequest(ctx context.Context, method, path string, body inte
 	return nil
 }
 
-func (c *Client) transformError(statusCode int, body []byte) error {
+func (c *Client) transformError(resp *http.Response, body []byte) error {
 	var errorData struct {
 		Hint string `json:"hint"`
 	}
 	json.Unmarshal(body, &errorData)
 
-	switch statusCode {
+	switch resp.StatusCode {
 	case 400:
-		return NewBadRequestError(errorData.Hint)
+		err := NewBadRequestError(errorData.Hint)
+		err.setResponse(resp, body)
+		return err
 	case 401:
-		return NewInvalidApiKeyError()
+		err := NewInvalidApiKeyError()
+		err.setResponse(resp, body)
+		return err
 	case 402:
-		return NewInsufficientCreditsError()
+		err := NewInsufficientCreditsError()
+		err.setResponse(resp, body)
+		return err
 	case 429:
-		return NewRateLimitExceededError()
+		err := NewRateLimitExceededError()
+		err.setResponse(resp, body)
+		return err
 	default:
-		return NewCreatomateError(errorData.Hint)
+		err := NewCreatomateError(errorData.Hint)
+		err.setResponse(resp, body)
+		return err
 	}
 }
 
-func isRenderInProgress(status RenderStatus) bool {
-	return status == RenderStatusPlanned ||
-		status == RenderStatusWaiting ||
-		status == RenderStatusTranscribing ||
-		status == RenderStatusRendering
+func (c *Client) userAgent() string {
+	userAgent := "Creatomate-Go/" + ClientVersion
+	if c.userAgentSuffix != "" {
+		userAgent += " " + c.userAgentSuffix
+	}
+	return userAgent
 }
 
 // transformObjectKeysToSnake converts map keys from camelCase to snake_case
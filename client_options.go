@@ -0,0 +1,66 @@
+package creatomate
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL overrides the API base URL, e.g. to point the client at a local test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets the http.Client used for all requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper used for all requests, whether it comes before or after
+// WithHTTPClient. The http.Client passed to WithHTTPClient is copied rather than modified.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithUserAgent appends a suffix to the User-Agent header, e.g. "my-service/2.3".
+func WithUserAgent(suffix string) Option {
+	return func(c *Client) {
+		c.userAgentSuffix = suffix
+	}
+}
+
+// WithRenderTimeout sets the timeout used by Render when it is called with a zero timeout.
+func WithRenderTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.renderTimeout = timeout
+	}
+}
+
+// WithMaxRenderTimeout sets the longest timeout Render accepts. Longer timeouts are reduced to
+// it. Defaults to 60 minutes; zero or a negative value removes the limit.
+func WithMaxRenderTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.maxRenderTimeout = timeout
+	}
+}
+
+// WithSourceValidation makes StartRender validate sources with Source.Validate before sending
+// them, so that invalid sources fail without a request. Sources given as a Source, a map, or
+// JSON in a json.RawMessage, []byte or string are validated as well. Media elements whose
+// source is set by the modifications of the render may leave it empty.
+func WithSourceValidation() Option {
+	return func(c *Client) {
+		c.validateSources = true
+	}
+}
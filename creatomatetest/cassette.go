@@ -0,0 +1,258 @@
+package creatomatetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// CassetteMode decides whether a Cassette records or replays requests.
+type CassetteMode int
+
+const (
+	// ModeAuto replays the cassette file if it exists and records it otherwise.
+	ModeAuto CassetteMode = iota
+
+	// ModeReplay only replays the cassette file. Requests that don't match fail.
+	ModeReplay
+
+	// ModeRecord sends every request and records it, replacing the cassette file on Save.
+	ModeRecord
+)
+
+const (
+	redacted = "REDACTED"
+
+	// The prefix of tokens created by creatomate.SignMetadata, and what they are recorded as.
+	signedMetadataPrefix = "cm1."
+	signedMetadata       = "SIGNED"
+)
+
+// Cassette is an http.RoundTripper that records API exchanges to a JSON file and replays
+// them deterministically. Requests are matched by method, path, query and JSON body, so a
+// change in the generated source shows up as a mismatch.
+//
+//	cassette, err := creatomatetest.NewCassette("testdata/render.json", creatomatetest.ModeAuto, nil)
+//	client := creatomate.NewClient(os.Getenv("CREATOMATE_API_KEY"), creatomate.WithTransport(cassette))
+//	defer cassette.Save()
+//
+// The Authorization header is never written to the file, and signed metadata is matched
+// regardless of its nonce and timestamp.
+type Cassette struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request. Body holds JSON re-encoded with sorted keys.
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response.
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// CassetteMismatchError is returned when a replayed request matches no recorded interaction.
+type CassetteMismatchError struct {
+	Request CassetteRequest
+}
+
+func (e *CassetteMismatchError) Error() string {
+	return fmt.Sprintf("cassette has no unused interaction for %s %s with body %s", e.Request.Method, e.Request.Path, e.Request.Body)
+}
+
+// NewCassette opens the cassette at path. In record mode, requests are sent with transport,
+// which defaults to http.DefaultTransport.
+func NewCassette(path string, mode CassetteMode, transport http.RoundTripper) (*Cassette, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	cassette := &Cassette{path: path, transport: transport}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case mode == ModeRecord || (mode == ModeAuto && errors.Is(err, os.ErrNotExist)):
+		cassette.recording = true
+		return cassette, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &cassette.interactions); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	cassette.used = make([]bool, len(cassette.interactions))
+	return cassette, nil
+}
+
+// Recording reports whether the cassette sends and records requests rather than replaying them.
+func (c *Cassette) Recording() bool {
+	return c.recording
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	request := newCassetteRequest(req, body)
+
+	if c.recording {
+		// The caller's request must not be modified, so send a copy with the body restored
+		outgoing := req.Clone(req.Context())
+		if body != nil {
+			outgoing.Body = io.NopCloser(bytes.NewReader(body))
+			outgoing.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+		return c.record(outgoing, request)
+	}
+	return c.replay(req, request)
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing when replaying.
+func (c *Cassette) Save() error {
+	if !c.recording {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+func (c *Cassette) record(req *http.Request, request CassetteRequest) (*http.Response, error) {
+	resp, err := c.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := CassetteResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       string(body),
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{Request: request, Response: response})
+	c.mu.Unlock()
+
+	return response.toHTTP(req), nil
+}
+
+func (c *Cassette) replay(req *http.Request, request CassetteRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, interaction := range c.interactions {
+		if c.used[i] || !interaction.Request.matches(request) {
+			continue
+		}
+		c.used[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+	return nil, &CassetteMismatchError{Request: request}
+}
+
+// readRequestBody reads and closes the body of the request, if it has one.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// newCassetteRequest captures the request with its body normalized and its API key redacted.
+func newCassetteRequest(req *http.Request, body []byte) CassetteRequest {
+	header := req.Header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", redacted)
+	}
+
+	return CassetteRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+		Header: header,
+		Body:   normalizeBody(body),
+	}
+}
+
+func (r CassetteRequest) matches(other CassetteRequest) bool {
+	return r.Method == other.Method && r.Path == other.Path && r.Query == other.Query && r.Body == other.Body
+}
+
+func (r CassetteResponse) toHTTP(req *http.Request) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(r.Body))),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// normalizeBody re-encodes JSON bodies with sorted keys, so that key order doesn't matter.
+// Metadata signed with creatomate.WithWebhookSecret holds a nonce and a timestamp that change
+// on every run, so it is replaced with a placeholder.
+func normalizeBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		if metadata, ok := object["metadata"].(string); ok && strings.HasPrefix(metadata, signedMetadataPrefix) {
+			object["metadata"] = signedMetadata
+		}
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
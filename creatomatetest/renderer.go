@@ -0,0 +1,189 @@
+package creatomatetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+)
+
+// Outcome describes how a render request handled by MemoryRenderer ends.
+type Outcome struct {
+	// The final status of the renders. Defaults to succeeded.
+	Status creatomate.RenderStatus
+
+	// The error message of failed renders.
+	ErrorMessage string
+
+	// The error returned instead of starting the renders, e.g. creatomate.NewInsufficientCreditsError().
+	Err error
+
+	// The number of renders started by the request. Defaults to 1.
+	Renders int
+}
+
+// MemoryRenderer is a creatomate.Renderer that keeps renders in memory without any HTTP.
+// Renders returned by StartRender are planned and move to the status of their outcome on their
+// first fetch. Outcomes with a status that is still in progress, such as rendering, never
+// finish, so Render returns a *creatomate.TimeoutError for them once the timeout expires.
+// Outcome functions may call the renderer themselves.
+//
+//	renderer := creatomatetest.NewMemoryRenderer()
+//	renderer.SetOutcome(creatomatetest.Outcome{Status: creatomate.RenderStatusFailed})
+//	pipeline := NewPipeline(renderer)
+type MemoryRenderer struct {
+	mu       sync.Mutex
+	outcome  func(options creatomate.RenderOptions) Outcome
+	renders  map[string]*memoryRender
+	requests []creatomate.RenderOptions
+	nextID   int
+}
+
+type memoryRender struct {
+	render  creatomate.Render
+	outcome Outcome
+}
+
+var _ creatomate.Renderer = (*MemoryRenderer)(nil)
+
+// NewMemoryRenderer creates a renderer whose renders succeed.
+func NewMemoryRenderer() *MemoryRenderer {
+	return &MemoryRenderer{renders: make(map[string]*memoryRender)}
+}
+
+// SetOutcome sets the outcome of every render request from now on.
+func (m *MemoryRenderer) SetOutcome(outcome Outcome) {
+	m.SetOutcomeFunc(func(creatomate.RenderOptions) Outcome {
+		return outcome
+	})
+}
+
+// SetOutcomeFunc sets a function that decides the outcome of every render request from now on.
+func (m *MemoryRenderer) SetOutcomeFunc(fn func(options creatomate.RenderOptions) Outcome) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outcome = fn
+}
+
+// Requests returns the options of every render request received so far.
+func (m *MemoryRenderer) Requests() []creatomate.RenderOptions {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]creatomate.RenderOptions(nil), m.requests...)
+}
+
+// Render starts the renders and fetches them once. A zero timeout waits for renders that don't
+// finish for creatomate.DefaultRenderTimeout, like Client.Render.
+func (m *MemoryRenderer) Render(ctx context.Context, options creatomate.RenderOptions, timeout time.Duration) ([]creatomate.Render, error) {
+	renders, err := m.StartRender(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	finished := make([]creatomate.Render, 0, len(renders))
+	var unfinished []creatomate.Render
+	for _, render := range renders {
+		updated, err := m.FetchRender(ctx, render.ID)
+		if err != nil {
+			return finished, err
+		}
+		if updated.Status.InProgress() {
+			unfinished = append(unfinished, *updated)
+			continue
+		}
+		finished = append(finished, *updated)
+	}
+	if len(unfinished) == 0 {
+		return finished, nil
+	}
+
+	// The renders never advance, so wait out the timeout like the client would
+	if timeout == 0 {
+		timeout = creatomate.DefaultRenderTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return finished, ctx.Err()
+	case <-timer.C:
+	}
+	return finished, creatomate.NewTimeoutErrorWithRenders(unfinished)
+}
+
+func (m *MemoryRenderer) StartRender(ctx context.Context, options creatomate.RenderOptions) ([]creatomate.Render, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.requests = append(m.requests, options)
+	outcomeFunc := m.outcome
+	m.mu.Unlock()
+
+	// Called without the lock, so that the function can use the renderer
+	outcome := Outcome{}
+	if outcomeFunc != nil {
+		outcome = outcomeFunc(options)
+	}
+	if outcome.Err != nil {
+		return nil, outcome.Err
+	}
+	if outcome.Status == "" {
+		outcome.Status = creatomate.RenderStatusSucceeded
+	}
+	if outcome.Renders <= 0 {
+		outcome.Renders = 1
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	renders := make([]creatomate.Render, outcome.Renders)
+	for i := range renders {
+		m.nextID++
+		renders[i] = creatomate.Render{
+			ID:            fmt.Sprintf("render-%d", m.nextID),
+			Status:        creatomate.RenderStatusPlanned,
+			TemplateID:    options.TemplateID,
+			TemplateTags:  options.Tags,
+			OutputFormat:  string(options.OutputFormat),
+			RenderScale:   1,
+			Modifications: options.Modifications,
+			WebhookURL:    options.WebhookURL,
+			Metadata:      options.Metadata,
+		}
+		m.renders[renders[i].ID] = &memoryRender{render: renders[i], outcome: outcome}
+	}
+	return renders, nil
+}
+
+func (m *MemoryRenderer) FetchRender(ctx context.Context, id string) (*creatomate.Render, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.renders[id]
+	if !ok {
+		return nil, creatomate.NewCreatomateError("Render not found")
+	}
+
+	render := &stored.render
+	if render.Status == creatomate.RenderStatusPlanned {
+		render.Status = stored.outcome.Status
+		switch render.Status {
+		case creatomate.RenderStatusSucceeded:
+			render.URL = "https://cdn.creatomate.test/" + render.ID
+		case creatomate.RenderStatusFailed:
+			render.ErrorMessage = stored.outcome.ErrorMessage
+		}
+	}
+
+	result := *render
+	return &result, nil
+}
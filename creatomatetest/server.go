@@ -0,0 +1,589 @@
+// Package creatomatetest provides a fake Creatomate API for tests.
+//
+//	server := creatomatetest.NewServer(creatomatetest.WithProgression(
+//	    creatomate.RenderStatusPlanned,
+//	    creatomate.RenderStatusRendering,
+//	    creatomate.RenderStatusSucceeded,
+//	))
+//	defer server.Close()
+//
+//	client := server.NewClient(creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)))
+//	renders, err := client.Render(ctx, creatomate.RenderOptions{Source: source}, time.Minute)
+//
+//	sources := server.Sources()
+//
+// Every fetch of a render advances it to the next status of its progression. When a render
+// finishes or is canceled and has a webhook URL, the server posts the render to it.
+package creatomatetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+)
+
+// The content served for the output of every render.
+var outputContent = []byte("fake creatomate output")
+
+// Server is a fake Creatomate API that implements POST /renders, GET /renders,
+// GET /renders/{id}, POST /renders/{id}/cancel and DELETE /renders/{id}. GET /renders
+// supports pagination and the filters of creatomate.ListRendersOptions.
+type Server struct {
+	// The base URL of the server, to be passed to creatomate.WithBaseURL.
+	URL string
+
+	server *httptest.Server
+
+	mu          sync.Mutex
+	apiKey      string
+	progression []creatomate.RenderStatus
+	latency     time.Duration
+	renders     map[string]*fakeRender
+	order       []string
+	nextID      int
+	failures    []failure
+	requests    []Request
+	deliveries  []Delivery
+	webhooks    *http.Client
+}
+
+type fakeRender struct {
+	render      creatomate.Render
+	progression []creatomate.RenderStatus
+	step        int
+	createdAt   time.Time
+}
+
+type failure struct {
+	route      string
+	statusCode int
+	hint       string
+}
+
+// Request is an API request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+
+	// The "source" property of a POST /renders request, if any.
+	Source json.RawMessage
+}
+
+// Delivery is a webhook delivered by the server.
+type Delivery struct {
+	URL        string
+	Render     creatomate.Render
+	StatusCode int
+	Err        error
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithProgression sets the statuses that every new render goes through, one per fetch.
+// Defaults to planned, rendering, succeeded.
+func WithProgression(statuses ...creatomate.RenderStatus) ServerOption {
+	return func(s *Server) {
+		s.progression = statuses
+	}
+}
+
+// WithLatency delays every response.
+func WithLatency(latency time.Duration) ServerOption {
+	return func(s *Server) {
+		s.latency = latency
+	}
+}
+
+// WithAPIKey makes the server reject requests that don't use the given API key.
+func WithAPIKey(apiKey string) ServerOption {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithWebhookClient sets the http.Client used to deliver webhooks.
+func WithWebhookClient(client *http.Client) ServerOption {
+	return func(s *Server) {
+		s.webhooks = client
+	}
+}
+
+// NewServer starts a fake API server. Call Close when done.
+func NewServer(options ...ServerOption) *Server {
+	s := &Server{
+		progression: []creatomate.RenderStatus{
+			creatomate.RenderStatusPlanned,
+			creatomate.RenderStatusRendering,
+			creatomate.RenderStatusSucceeded,
+		},
+		renders:  make(map[string]*fakeRender),
+		webhooks: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// NewClient creates a client that sends its requests to the server.
+func (s *Server) NewClient(options ...creatomate.Option) *creatomate.Client {
+	apiKey := s.apiKey
+	if apiKey == "" {
+		apiKey = "test-api-key"
+	}
+	options = append([]creatomate.Option{creatomate.WithBaseURL(s.URL)}, options...)
+	return creatomate.NewClient(apiKey, options...)
+}
+
+// SetProgression changes the statuses that renders created from now on go through.
+func (s *Server) SetProgression(statuses ...creatomate.RenderStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.progression = statuses
+}
+
+// FailNext makes the next request to the route, e.g. creatomate.RouteStartRender, fail with
+// the given status code and hint. An empty route matches any request. Failures are queued,
+// so calling FailNext repeatedly fails multiple requests.
+func (s *Server) FailNext(route string, statusCode int, hint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{route: route, statusCode: statusCode, hint: hint})
+}
+
+// Requests returns the API requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Sources returns the sources of the renders started so far.
+func (s *Server) Sources() []json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sources []json.RawMessage
+	for _, request := range s.requests {
+		if request.Source != nil {
+			sources = append(sources, request.Source)
+		}
+	}
+	return sources
+}
+
+// Deliveries returns the webhooks delivered so far.
+func (s *Server) Deliveries() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Delivery(nil), s.deliveries...)
+}
+
+// Render returns the current state of a render.
+func (s *Server) Render(id string) (creatomate.Render, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	render, ok := s.renders[id]
+	if !ok {
+		return creatomate.Render{}, false
+	}
+	return render.render, true
+}
+
+// Advance moves a render to the next status of its progression without fetching it.
+func (s *Server) Advance(id string) (creatomate.Render, bool) {
+	s.mu.Lock()
+	render, ok := s.renders[id]
+	if !ok {
+		s.mu.Unlock()
+		return creatomate.Render{}, false
+	}
+	finished := s.advance(render)
+	result := render.render
+	s.mu.Unlock()
+
+	if finished {
+		s.deliverWebhook(result)
+	}
+	return result, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/files/") {
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(outputContent))
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	latency := s.latency
+	request := Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body}
+	if r.Method == http.MethodPost && r.URL.Path == "/renders" {
+		var payload struct {
+			Source json.RawMessage `json:"source"`
+		}
+		json.Unmarshal(body, &payload)
+		request.Source = payload.Source
+	}
+	s.requests = append(s.requests, request)
+	injected := s.takeFailure(creatomate.RouteOf(r.Method, r.URL.Path))
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if s.apiKey != "" && r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeError(w, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+	if injected != nil {
+		if injected.statusCode == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		writeError(w, injected.statusCode, injected.hint)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/renders":
+		s.startRender(w, body)
+	case r.Method == http.MethodGet && r.URL.Path == "/renders":
+		s.listRenders(w, r.URL.Query())
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/renders/") && strings.HasSuffix(r.URL.Path, "/cancel"):
+		s.cancelRender(w, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/renders/"), "/cancel"))
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/renders/"):
+		s.fetchRender(w, strings.TrimPrefix(r.URL.Path, "/renders/"))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/renders/"):
+		s.deleteRender(w, strings.TrimPrefix(r.URL.Path, "/renders/"))
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) startRender(w http.ResponseWriter, body []byte) {
+	var options struct {
+		OutputFormat  string                 `json:"output_format"`
+		TemplateID    string                 `json:"template_id"`
+		Tags          []string               `json:"tags"`
+		Source        map[string]interface{} `json:"source"`
+		Modifications map[string]interface{} `json:"modifications"`
+		WebhookURL    string                 `json:"webhook_url"`
+		Metadata      string                 `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &options); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	if options.Source == nil && options.TemplateID == "" {
+		writeError(w, http.StatusBadRequest, "Either source or template_id must be provided")
+		return
+	}
+
+	outputFormat := options.OutputFormat
+	if format, ok := options.Source["output_format"].(string); ok && outputFormat == "" {
+		outputFormat = format
+	}
+	if outputFormat == "" {
+		outputFormat = "mp4"
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("render-%d", s.nextID)
+	render := &fakeRender{
+		render: creatomate.Render{
+			ID:            id,
+			TemplateID:    options.TemplateID,
+			TemplateTags:  options.Tags,
+			OutputFormat:  outputFormat,
+			RenderScale:   1,
+			Modifications: options.Modifications,
+			WebhookURL:    options.WebhookURL,
+			Metadata:      options.Metadata,
+		},
+		progression: append([]creatomate.RenderStatus(nil), s.progression...),
+		createdAt:   time.Now(),
+	}
+	if len(render.progression) > 0 {
+		render.render.Status = render.progression[0]
+	}
+	s.renders[id] = render
+	s.order = append(s.order, id)
+	result := render.render
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusAccepted, []creatomate.Render{result})
+}
+
+// listRenders writes the requested page of the renders that match the filters of the query,
+// in the order they were started.
+func (s *Server) listRenders(w http.ResponseWriter, query url.Values) {
+	filter, err := parseListFilter(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := queryInt(query, "page", 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := queryInt(query, "limit", 100)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	renders := make([]creatomate.Render, 0, len(s.order))
+	for _, id := range s.order {
+		if render := s.renders[id]; filter.matches(render) {
+			renders = append(renders, render.render)
+		}
+	}
+	s.mu.Unlock()
+
+	start := (page - 1) * limit
+	if start > len(renders) {
+		start = len(renders)
+	}
+	end := start + limit
+	if end > len(renders) {
+		end = len(renders)
+	}
+	writeJSON(w, http.StatusOK, renders[start:end])
+}
+
+func (s *Server) fetchRender(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	render, ok := s.renders[id]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "Render not found")
+		return
+	}
+	finished := s.advance(render)
+	result := render.render
+	s.mu.Unlock()
+
+	if finished {
+		s.deliverWebhook(result)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// cancelRender fails an unfinished render with a cancellation message.
+func (s *Server) cancelRender(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	render, ok := s.renders[id]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "Render not found")
+		return
+	}
+	if status := render.render.Status; status == creatomate.RenderStatusSucceeded || status == creatomate.RenderStatusFailed {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "The render has already finished")
+		return
+	}
+	render.render.Status = creatomate.RenderStatusFailed
+	render.render.ErrorMessage = "The render was canceled."
+	render.progression = []creatomate.RenderStatus{creatomate.RenderStatusFailed}
+	render.step = 0
+	result := render.render
+	s.mu.Unlock()
+
+	s.deliverWebhook(result)
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) deleteRender(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	_, ok := s.renders[id]
+	if ok {
+		delete(s.renders, id)
+		for i, orderID := range s.order {
+			if orderID == id {
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "Render not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// advance moves the render to its next status. It returns true if the render just finished.
+// The caller must hold s.mu.
+func (s *Server) advance(render *fakeRender) bool {
+	if render.step+1 >= len(render.progression) {
+		return false
+	}
+	render.step++
+	render.render.Status = render.progression[render.step]
+
+	switch render.render.Status {
+	case creatomate.RenderStatusSucceeded:
+		render.render.URL = s.URL + "/files/" + render.render.ID + "." + render.render.OutputFormat
+		render.render.FileSize = int64(len(outputContent))
+		return true
+	case creatomate.RenderStatusFailed:
+		render.render.ErrorMessage = "The render failed."
+		return true
+	}
+	return false
+}
+
+// takeFailure removes and returns the first injected failure that matches the route.
+// The caller must hold s.mu.
+func (s *Server) takeFailure(requestRoute string) *failure {
+	for i, f := range s.failures {
+		if f.route == "" || f.route == requestRoute {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			return &f
+		}
+	}
+	return nil
+}
+
+func (s *Server) deliverWebhook(render creatomate.Render) {
+	if render.WebhookURL == "" {
+		return
+	}
+
+	delivery := Delivery{URL: render.WebhookURL, Render: render}
+	body, _ := json.Marshal(render)
+	resp, err := s.webhooks.Post(render.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		delivery.Err = err
+	} else {
+		delivery.StatusCode = resp.StatusCode
+		resp.Body.Close()
+	}
+
+	s.mu.Lock()
+	s.deliveries = append(s.deliveries, delivery)
+	s.mu.Unlock()
+}
+
+// listFilter holds the filters of a GET /renders request.
+type listFilter struct {
+	statuses      []string
+	templateID    string
+	tags          []string
+	createdAfter  time.Time
+	createdBefore time.Time
+}
+
+func parseListFilter(query url.Values) (listFilter, error) {
+	filter := listFilter{
+		statuses:   splitList(query.Get("status")),
+		templateID: query.Get("template_id"),
+		tags:       splitList(query.Get("tags")),
+	}
+	for name, target := range map[string]*time.Time{"created_after": &filter.createdAfter, "created_before": &filter.createdBefore} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return listFilter{}, fmt.Errorf("Invalid %s", name)
+			}
+			*target = parsed
+		}
+	}
+	return filter, nil
+}
+
+// matches reports whether the render passes every filter. The caller must hold s.mu.
+func (f listFilter) matches(render *fakeRender) bool {
+	if len(f.statuses) > 0 && !contains(f.statuses, string(render.render.Status)) {
+		return false
+	}
+	if f.templateID != "" && render.render.TemplateID != f.templateID {
+		return false
+	}
+	for _, tag := range f.tags {
+		if !contains(render.render.TemplateTags, tag) {
+			return false
+		}
+	}
+	if !f.createdAfter.IsZero() && render.createdAt.Before(f.createdAfter) {
+		return false
+	}
+	if !f.createdBefore.IsZero() && !render.createdAt.Before(f.createdBefore) {
+		return false
+	}
+	return true
+}
+
+// queryInt returns the positive integer query parameter with the given name, or fallback if
+// it is not set.
+func queryInt(query url.Values, name string, fallback int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("Invalid %s", name)
+	}
+	return number, nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, hint string) {
+	writeJSON(w, statusCode, map[string]string{"hint": hint})
+}
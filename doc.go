@@ -52,6 +52,14 @@
 //   - Keyframe animations (custom property animations over time)
 //   - Text-specific animations (typewriter, slide, reveal, etc.)
 //
+// Properties that can be animated have the type properties.ValueOrKeyframes[T], which holds
+// either a static value or keyframes of type T:
+//
+//	elements.ElementProperties{
+//	    X:       creatomate.Static[any]("50%"),
+//	    Opacity: creatomate.Animated(creatomate.NewKeyframe[any]("0%", 0), creatomate.NewKeyframe[any]("100%", 1)),
+//	}
+//
 // # Error Handling
 //
 // The SDK provides typed errors for all API error conditions:
@@ -61,4 +69,14 @@
 //   - RateLimitExceededError
 //   - ConnectionError
 //   - TimeoutError
+//
+// All of them embed CreatomateError, which carries the HTTP status code, response headers,
+// request ID, raw body and underlying cause, and can be extracted with errors.As:
+//
+//	var apiErr *creatomate.CreatomateError
+//	if errors.As(err, &apiErr) {
+//	    log.Printf("status=%d request_id=%s", apiErr.StatusCode, apiErr.RequestID)
+//	}
+//
+// IsRetryable and IsTemporary classify errors for callers that implement their own retries.
 package creatomate
\ No newline at end of file
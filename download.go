@@ -0,0 +1,360 @@
+package creatomate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DownloadOption configures Download and DownloadToFile.
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	snapshot bool
+	progress func(written, total int64)
+}
+
+// DownloadSnapshot downloads the snapshot image of the render instead of its output.
+func DownloadSnapshot() DownloadOption {
+	return func(o *downloadOptions) {
+		o.snapshot = true
+	}
+}
+
+// DownloadProgress calls fn after every chunk with the number of bytes written so far and
+// the total size, or -1 if the size is unknown.
+func DownloadProgress(fn func(written, total int64)) DownloadOption {
+	return func(o *downloadOptions) {
+		o.progress = fn
+	}
+}
+
+// Download streams the output of a finished render to w. Interrupted transfers are resumed
+// with Range requests according to the client's retry policy. The Timeout of the http.Client
+// limits the wait for each chunk of data rather than the whole transfer.
+func (c *Client) Download(ctx context.Context, render Render, w io.Writer, options ...DownloadOption) error {
+	_, err := c.download(ctx, render, w, 0, options)
+	return err
+}
+
+// DownloadToFile downloads the output of a finished render to path. Data is written to
+// path + ".part" first, and the URL it comes from to path + ".part.url", so that later calls
+// resume the download if it fails. A partial file of another URL, or one that doesn't match
+// the size of the file, is discarded and downloaded again.
+func (c *Client) DownloadToFile(ctx context.Context, render Render, path string, options ...DownloadOption) error {
+	url, err := downloadURL(render, options)
+	if err != nil {
+		return err
+	}
+
+	partPath := path + ".part"
+	urlPath := partPath + ".url"
+	previousURL, _ := os.ReadFile(urlPath)
+	resume := string(previousURL) == url
+
+	err = c.downloadToPart(ctx, render, partPath, urlPath, url, resume, options)
+	var sizeError *downloadSizeError
+	if errors.As(err, &sizeError) && resume {
+		// The partial file may not be what the URL serves now, so start over
+		err = c.downloadToPart(ctx, render, partPath, urlPath, url, false, options)
+	}
+	if errors.As(err, &sizeError) {
+		// The partial file can't be trusted, so start over next time
+		os.Remove(partPath)
+		os.Remove(urlPath)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return err
+	}
+	os.Remove(urlPath)
+	return nil
+}
+
+// downloadToPart downloads to the partial file, appending to it if resume is true and
+// truncating it otherwise.
+func (c *Client) downloadToPart(ctx context.Context, render Render, partPath, urlPath, url string, resume bool, options []DownloadOption) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !resume {
+		flags |= os.O_TRUNC
+		if err := os.WriteFile(urlPath, []byte(url), 0o644); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	_, err = c.download(ctx, render, file, info.Size(), options)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+type downloadSizeError struct {
+	written  int64
+	expected int64
+}
+
+func (e *downloadSizeError) Error() string {
+	return fmt.Sprintf("download incomplete: got %d of %d bytes", e.written, e.expected)
+}
+
+// download writes the file to w, starting at offset bytes which w already holds.
+func (c *Client) download(ctx context.Context, render Render, w io.Writer, offset int64, options []DownloadOption) (int64, error) {
+	var opts downloadOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	url, err := downloadURL(render, options)
+	if err != nil {
+		return 0, err
+	}
+	expected := render.FileSize
+	if opts.snapshot {
+		expected = 0
+	}
+
+	written := offset
+	total := int64(-1)
+	if expected > 0 {
+		total = expected
+	}
+
+	for attempt := 1; ; attempt++ {
+		n, size, err := c.downloadAttempt(ctx, url, w, written, total, opts.progress)
+		written += n
+		if size > 0 {
+			total = size
+		}
+		if err == nil {
+			break
+		}
+		if attempt >= c.retryPolicy.maxAttempts(http.MethodGet, err) || ctx.Err() != nil || !IsRetryable(err) {
+			return written, err
+		}
+
+		delay := c.retryPolicy.backoff(attempt, -1)
+		if c.retryPolicy.OnRetry != nil {
+			c.retryPolicy.OnRetry(RetryAttempt{
+				Method:  http.MethodGet,
+				Path:    url,
+				Attempt: attempt,
+				Err:     err,
+				Delay:   delay,
+			})
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return written, err
+		case <-timer.C:
+		}
+	}
+
+	if expected > 0 && written != expected {
+		return written, &downloadSizeError{written: written, expected: expected}
+	}
+	return written, nil
+}
+
+// downloadURL returns the URL of the output or the snapshot of the render.
+func downloadURL(render Render, options []DownloadOption) (string, error) {
+	var opts downloadOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	url := render.URL
+	if opts.snapshot {
+		url = render.SnapshotURL
+	}
+	if url == "" {
+		return "", fmt.Errorf("render %s has no URL to download", render.ID)
+	}
+	return url, nil
+}
+
+// downloadAttempt performs a single request starting at offset. It returns the number of
+// bytes written and the total size of the file if the response reveals it.
+// The http.Client's Timeout limits the whole exchange, which large files can't finish in, so
+// it is applied to every read instead.
+func (c *Client) downloadAttempt(ctx context.Context, url string, w io.Writer, offset, total int64, progress func(written, total int64)) (int64, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	deadline := newReadDeadline(c.httpClient.Timeout, cancel)
+	defer deadline.stop()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent())
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, 0, deadline.connectionError(err)
+	}
+	defer resp.Body.Close()
+
+	body := io.Reader(&deadlineReader{r: resp.Body, deadline: deadline})
+	switch {
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		size := parseContentRangeSize(resp.Header.Get("Content-Range"), total)
+		if offset != size {
+			// More was written than the file holds
+			return 0, size, &downloadSizeError{written: offset, expected: size}
+		}
+		// The previous attempt already wrote the whole file
+		return 0, size, nil
+	case resp.StatusCode == http.StatusPartialContent:
+		total = parseContentRangeSize(resp.Header.Get("Content-Range"), total)
+	case resp.StatusCode == http.StatusOK:
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+		if offset > 0 {
+			// The server ignored the Range header, so skip what was already written
+			if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+				return 0, total, deadline.connectionError(err)
+			}
+		}
+	default:
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		downloadError := NewCreatomateError(fmt.Sprintf("Download failed with status %d", resp.StatusCode))
+		downloadError.setResponse(resp, respBody)
+		return 0, 0, downloadError
+	}
+
+	n, err := io.Copy(&downloadWriter{w: w, written: offset, total: total, progress: progress}, body)
+	if err != nil {
+		// Only read errors are worth retrying
+		var writeErr *downloadWriteError
+		if errors.As(err, &writeErr) {
+			return n, total, writeErr.err
+		}
+		return n, total, deadline.connectionError(err)
+	}
+	return n, total, nil
+}
+
+// readDeadline cancels a download that receives no data for the timeout. Zero means no
+// deadline.
+type readDeadline struct {
+	timeout time.Duration
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+func newReadDeadline(timeout time.Duration, cancel context.CancelFunc) *readDeadline {
+	d := &readDeadline{timeout: timeout}
+	if timeout > 0 {
+		d.timer = time.AfterFunc(timeout, func() {
+			d.expired.Store(true)
+			cancel()
+		})
+	}
+	return d
+}
+
+// extend restarts the deadline after data was received.
+func (d *readDeadline) extend() {
+	if d.timer != nil {
+		d.timer.Reset(d.timeout)
+	}
+}
+
+func (d *readDeadline) stop() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
+
+// connectionError wraps a failed request or read. When the deadline caused it, the cause is
+// replaced so that the stalled transfer is retried rather than treated as canceled.
+func (d *readDeadline) connectionError(err error) *ConnectionError {
+	if d.expired.Load() {
+		err = fmt.Errorf("no data received for %s", d.timeout)
+	}
+	return newConnectionErrorWithCause(err)
+}
+
+type deadlineReader struct {
+	r        io.Reader
+	deadline *readDeadline
+}
+
+func (r *deadlineReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.deadline.extend()
+	}
+	return n, err
+}
+
+// parseContentRangeSize returns the total size from a header like "bytes 100-199/200".
+func parseContentRangeSize(contentRange string, fallback int64) int64 {
+	i := strings.LastIndexByte(contentRange, '/')
+	if i < 0 {
+		return fallback
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return size
+}
+
+// downloadWriter reports progress and marks write errors, so that they can be told apart
+// from read errors.
+type downloadWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+type downloadWriteError struct {
+	err error
+}
+
+func (e *downloadWriteError) Error() string {
+	return e.err.Error()
+}
+
+func (d *downloadWriter) Write(b []byte) (int, error) {
+	n, err := d.w.Write(b)
+	d.written += int64(n)
+	if d.progress != nil {
+		d.progress(d.written, d.total)
+	}
+	if err != nil {
+		return n, &downloadWriteError{err: err}
+	}
+	return n, nil
+}
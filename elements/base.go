@@ -8,8 +8,6 @@ import (
 	"github.com/Lakeshore-Labs/creatomate-go/properties"
 )
 
-type ValueOrKeyframes[T any] interface{}
-
 type ElementBase interface {
 	ToMap() map[string]interface{}
 }
@@ -18,6 +16,9 @@ type ElementProperties struct {
 	// A unique identifier for this element.
 	ID string `json:"id,omitempty"`
 
+	// The name of the element, used to refer to it in template modifications.
+	Name string `json:"name,omitempty"`
+
 	// The track number on which this element is placed.
 	Track *int `json:"track,omitempty"`
 
@@ -28,124 +29,124 @@ type ElementProperties struct {
 	Duration interface{} `json:"duration,omitempty"` // number or string
 
 	// The x-axis position of the element in the composition.
-	X ValueOrKeyframes[interface{}] `json:"x,omitempty"`
+	X properties.ValueOrKeyframes[interface{}] `json:"x,omitempty"`
 
 	// The y-axis position of the element in the composition.
-	Y ValueOrKeyframes[interface{}] `json:"y,omitempty"`
+	Y properties.ValueOrKeyframes[interface{}] `json:"y,omitempty"`
 
 	// The width of the element in relation to the composition.
-	Width ValueOrKeyframes[interface{}] `json:"width,omitempty"`
+	Width properties.ValueOrKeyframes[interface{}] `json:"width,omitempty"`
 
 	// The height of the element in relation to the composition.
-	Height ValueOrKeyframes[interface{}] `json:"height,omitempty"`
+	Height properties.ValueOrKeyframes[interface{}] `json:"height,omitempty"`
 
 	// Using this property, the element will be constrained to a particular aspect ratio.
-	AspectRatio ValueOrKeyframes[float64] `json:"aspect_ratio,omitempty"`
+	AspectRatio properties.ValueOrKeyframes[float64] `json:"aspect_ratio,omitempty"`
 
 	// Padding of the element on the horizontal axis.
-	XPadding ValueOrKeyframes[interface{}] `json:"x_padding,omitempty"`
+	XPadding properties.ValueOrKeyframes[interface{}] `json:"x_padding,omitempty"`
 
 	// Padding of the element on the vertical axis.
-	YPadding ValueOrKeyframes[interface{}] `json:"y_padding,omitempty"`
+	YPadding properties.ValueOrKeyframes[interface{}] `json:"y_padding,omitempty"`
 
 	// The order in which the elements are rendered.
-	ZIndex ValueOrKeyframes[int] `json:"z_index,omitempty"`
+	ZIndex properties.ValueOrKeyframes[int] `json:"z_index,omitempty"`
 
 	// The element's origin from which its x-axis position, scale, rotate, and skew are applied.
-	XAnchor ValueOrKeyframes[interface{}] `json:"x_anchor,omitempty"`
+	XAnchor properties.ValueOrKeyframes[interface{}] `json:"x_anchor,omitempty"`
 
 	// The element's origin from which its y-axis position, scale, rotate, and skew are applied.
-	YAnchor ValueOrKeyframes[interface{}] `json:"y_anchor,omitempty"`
+	YAnchor properties.ValueOrKeyframes[interface{}] `json:"y_anchor,omitempty"`
 
 	// The horizontal scale transformation in percent.
-	XScale ValueOrKeyframes[interface{}] `json:"x_scale,omitempty"`
+	XScale properties.ValueOrKeyframes[interface{}] `json:"x_scale,omitempty"`
 
 	// The vertical scale transformation in percent.
-	YScale ValueOrKeyframes[interface{}] `json:"y_scale,omitempty"`
+	YScale properties.ValueOrKeyframes[interface{}] `json:"y_scale,omitempty"`
 
 	// The horizontal skew transformation in degrees.
-	XSkew ValueOrKeyframes[interface{}] `json:"x_skew,omitempty"`
+	XSkew properties.ValueOrKeyframes[interface{}] `json:"x_skew,omitempty"`
 
 	// The vertical skew transformation in degrees.
-	YSkew ValueOrKeyframes[interface{}] `json:"y_skew,omitempty"`
+	YSkew properties.ValueOrKeyframes[interface{}] `json:"y_skew,omitempty"`
 
 	// Rotates the element along the x-axis.
-	XRotation ValueOrKeyframes[interface{}] `json:"x_rotation,omitempty"`
+	XRotation properties.ValueOrKeyframes[interface{}] `json:"x_rotation,omitempty"`
 
 	// Rotates the element along the y-axis.
-	YRotation ValueOrKeyframes[interface{}] `json:"y_rotation,omitempty"`
+	YRotation properties.ValueOrKeyframes[interface{}] `json:"y_rotation,omitempty"`
 
 	// Rotates the element along the z-axis.
-	ZRotation ValueOrKeyframes[interface{}] `json:"z_rotation,omitempty"`
+	ZRotation properties.ValueOrKeyframes[interface{}] `json:"z_rotation,omitempty"`
 
 	// The distance between the z=0 plane and the camera.
-	Perspective ValueOrKeyframes[interface{}] `json:"perspective,omitempty"`
+	Perspective properties.ValueOrKeyframes[interface{}] `json:"perspective,omitempty"`
 
 	// Set to false to hide the backface of the element when rotated.
-	BackfaceVisible ValueOrKeyframes[bool] `json:"backface_visible,omitempty"`
+	BackfaceVisible properties.ValueOrKeyframes[bool] `json:"backface_visible,omitempty"`
 
 	// The position of the element's content on the x-axis.
-	XAlignment ValueOrKeyframes[interface{}] `json:"x_alignment,omitempty"`
+	XAlignment properties.ValueOrKeyframes[interface{}] `json:"x_alignment,omitempty"`
 
 	// The position of the element's content on the y-axis.
-	YAlignment ValueOrKeyframes[interface{}] `json:"y_alignment,omitempty"`
+	YAlignment properties.ValueOrKeyframes[interface{}] `json:"y_alignment,omitempty"`
 
 	// The shadow.
-	Shadow ValueOrKeyframes[*properties.Shadow] `json:"shadow,omitempty"`
+	Shadow properties.ValueOrKeyframes[*properties.Shadow] `json:"shadow,omitempty"`
 
 	// The shadow color, or null to disable it.
-	ShadowColor ValueOrKeyframes[string] `json:"shadow_color,omitempty"`
+	ShadowColor properties.ValueOrKeyframes[string] `json:"shadow_color,omitempty"`
 
 	// The blurriness of the shadow.
-	ShadowBlur ValueOrKeyframes[interface{}] `json:"shadow_blur,omitempty"`
+	ShadowBlur properties.ValueOrKeyframes[interface{}] `json:"shadow_blur,omitempty"`
 
 	// The offset of the shadow on the x-axis.
-	ShadowX ValueOrKeyframes[interface{}] `json:"shadow_x,omitempty"`
+	ShadowX properties.ValueOrKeyframes[interface{}] `json:"shadow_x,omitempty"`
 
 	// The offset of the shadow on the y-axis.
-	ShadowY ValueOrKeyframes[interface{}] `json:"shadow_y,omitempty"`
+	ShadowY properties.ValueOrKeyframes[interface{}] `json:"shadow_y,omitempty"`
 
 	// When set to true, the element's content is clipped to its borders.
-	Clip ValueOrKeyframes[bool] `json:"clip,omitempty"`
+	Clip properties.ValueOrKeyframes[bool] `json:"clip,omitempty"`
 
 	// The opacity of the element.
-	Opacity ValueOrKeyframes[interface{}] `json:"opacity,omitempty"`
+	Opacity properties.ValueOrKeyframes[interface{}] `json:"opacity,omitempty"`
 
 	// The blend mode of the element.
-	BlendMode ValueOrKeyframes[properties.BlendMode] `json:"blend_mode,omitempty"`
+	BlendMode properties.ValueOrKeyframes[properties.BlendMode] `json:"blend_mode,omitempty"`
 
 	// The color filter that is applied to the element.
-	ColorFilter ValueOrKeyframes[interface{}] `json:"color_filter,omitempty"`
+	ColorFilter properties.ValueOrKeyframes[interface{}] `json:"color_filter,omitempty"`
 
 	// This parameter allows you to control the color filter, such as the intensity.
-	ColorFilterValue ValueOrKeyframes[float64] `json:"color_filter_value,omitempty"`
+	ColorFilterValue properties.ValueOrKeyframes[float64] `json:"color_filter_value,omitempty"`
 
 	// A color that is applied on top the element.
-	ColorOverlay ValueOrKeyframes[string] `json:"color_overlay,omitempty"`
+	ColorOverlay properties.ValueOrKeyframes[string] `json:"color_overlay,omitempty"`
 
 	// The blur.
-	Blur ValueOrKeyframes[*properties.Blur] `json:"blur,omitempty"`
+	Blur properties.ValueOrKeyframes[*properties.Blur] `json:"blur,omitempty"`
 
 	// The radius of the blur that is applied to the element.
-	BlurRadius ValueOrKeyframes[float64] `json:"blur_radius,omitempty"`
+	BlurRadius properties.ValueOrKeyframes[float64] `json:"blur_radius,omitempty"`
 
 	// The algorithm used to blur the element.
-	BlurMode ValueOrKeyframes[properties.BlurMode] `json:"blur_mode,omitempty"`
+	BlurMode properties.ValueOrKeyframes[properties.BlurMode] `json:"blur_mode,omitempty"`
 
 	// By setting the mask mode, the element is used as a mask.
-	MaskMode ValueOrKeyframes[properties.MaskMode] `json:"mask_mode,omitempty"`
+	MaskMode properties.ValueOrKeyframes[properties.MaskMode] `json:"mask_mode,omitempty"`
 
 	// When set to true, the element is repeated in its composition.
-	Repeat ValueOrKeyframes[bool] `json:"repeat,omitempty"`
+	Repeat properties.ValueOrKeyframes[bool] `json:"repeat,omitempty"`
 
 	// The warp.
-	Warp ValueOrKeyframes[*properties.Warp] `json:"warp,omitempty"`
+	Warp properties.ValueOrKeyframes[*properties.Warp] `json:"warp,omitempty"`
 
 	// This parameter is used in conjunction with warp_matrix.
-	WarpMode ValueOrKeyframes[properties.WarpMode] `json:"warp_mode,omitempty"`
+	WarpMode properties.ValueOrKeyframes[properties.WarpMode] `json:"warp_mode,omitempty"`
 
 	// Array of points that control the warp effect.
-	WarpMatrix ValueOrKeyframes[[][]properties.WarpPoint] `json:"warp_matrix,omitempty"`
+	WarpMatrix properties.ValueOrKeyframes[[][]properties.WarpPoint] `json:"warp_matrix,omitempty"`
 
 	// An animation used as transition between this and the previous element.
 	Transition interface{} `json:"transition,omitempty"`
@@ -158,6 +159,10 @@ type ElementProperties struct {
 
 	// An array of animation keyframes.
 	Animations []interface{} `json:"animations,omitempty"`
+
+	// Properties that have no field of their own, such as locked and dynamic of sources made
+	// in the editor. They are written as they are, unless a field sets the same property.
+	Extra map[string]interface{} `json:"-"`
 }
 
 type BaseElement struct {
@@ -182,6 +187,13 @@ func (e *BaseElement) ToMap() map[string]interface{} {
 		if err := json.Unmarshal(propsJSON, &result); err != nil {
 			return map[string]interface{}{"type": e.Type}
 		}
+
+		// Remove nil values
+		for k, v := range result {
+			if v == nil {
+				delete(result, k)
+			}
+		}
 	}
 	
 	// Handle animations (enter, exit, transition)
@@ -243,10 +255,11 @@ func (e *BaseElement) ToMap() map[string]interface{} {
 		}
 	}
 	
-	// Remove nil values
-	for k, v := range result {
-		if v == nil {
-			delete(result, k)
+	if common := elementProperties(e.Properties); common != nil {
+		for k, v := range common.Extra {
+			if _, ok := result[k]; !ok {
+				result[k] = v
+			}
 		}
 	}
 	
@@ -254,6 +267,24 @@ func (e *BaseElement) ToMap() map[string]interface{} {
 	return result
 }
 
+// elementProperties returns the ElementProperties embedded in the properties of an element,
+// or nil if there are none.
+func elementProperties(props interface{}) *ElementProperties {
+	v := reflect.ValueOf(props)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	common := v.FieldByName("ElementProperties")
+	if !common.IsValid() || common.Type() != reflect.TypeOf(ElementProperties{}) {
+		return nil
+	}
+	result := common.Interface().(ElementProperties)
+	return &result
+}
+
 // expandProperties expands properties that have ToMap method
 func expandProperties(properties map[string]interface{}) map[string]interface{} {
 	expanded := make(map[string]interface{})
@@ -321,6 +352,12 @@ func structToMapWithExpansion(obj interface{}) map[string]interface{} {
 		
 		value := fieldValue.Interface()
 		
+		// Unwrap ValueOrKeyframes into the static value or the keyframes
+		if property, ok := value.(interface{ Interface() interface{} }); ok {
+			result[fieldName] = property.Interface()
+			continue
+		}
+		
 		// Handle embedded structs
 		if field.Anonymous && fieldValue.Kind() == reflect.Struct {
 			if embedded := structToMapWithExpansion(value); embedded != nil {
@@ -337,7 +374,9 @@ func structToMapWithExpansion(obj interface{}) map[string]interface{} {
 				// Expand the property
 				nestedProps := valueWithMap.ToMap()
 				for nestedKey, nestedValue := range nestedProps {
-					result[nestedKey] = nestedValue
+					if nestedValue != nil {
+						result[nestedKey] = nestedValue
+					}
 				}
 				continue
 			}
@@ -370,6 +409,10 @@ func isZeroValue(v reflect.Value) bool {
 		return v.Float() == 0
 	case reflect.Interface, reflect.Ptr:
 		return v.IsNil()
+	case reflect.Struct:
+		if zero, ok := v.Interface().(interface{ IsZero() bool }); ok {
+			return zero.IsZero()
+		}
 	}
 	return false
 }
\ No newline at end of file
@@ -0,0 +1,226 @@
+package elements
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FromMap converts the JSON representation of an element, as produced by ToMap, back into
+// the element type named by its "type" property. Properties that the type has no field for
+// are kept in ElementProperties.Extra. Elements of an unknown type are returned unchanged as
+// a map.
+func FromMap(data map[string]interface{}) (interface{}, error) {
+	elementType, _ := data["type"].(string)
+
+	switch elementType {
+	case "video":
+		var props VideoProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		return NewVideo(props), nil
+	case "image":
+		var props ImageProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		return NewImage(props), nil
+	case "text":
+		var props TextProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		return NewText(props), nil
+	case "audio":
+		var props AudioProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		return NewAudio(props), nil
+	case "shape":
+		var props ShapeProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		return NewShape(props), nil
+	case "rectangle":
+		var props RectangleProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		return NewRectangle(props), nil
+	case "ellipse":
+		var props EllipseProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		return NewEllipse(props), nil
+	case "composition":
+		var props CompositionProperties
+		if err := decodeProperties(data, &props); err != nil {
+			return nil, err
+		}
+		children, err := FromMaps(props.Elements)
+		if err != nil {
+			return nil, err
+		}
+		props.Elements = children
+		return NewComposition(props), nil
+	}
+
+	return data, nil
+}
+
+// FromMaps converts a list of elements in their JSON representation using FromMap.
+func FromMaps(list []interface{}) ([]interface{}, error) {
+	if list == nil {
+		return nil, nil
+	}
+
+	result := make([]interface{}, len(list))
+	for i, item := range list {
+		data, ok := item.(map[string]interface{})
+		if !ok {
+			result[i] = item
+			continue
+		}
+
+		element, err := FromMap(data)
+		if err != nil {
+			return nil, fmt.Errorf("elements[%d]: %w", i, err)
+		}
+		result[i] = element
+	}
+	return result, nil
+}
+
+// decodeProperties fills props from the JSON representation of an element. Properties without
+// a field are kept in Extra.
+func decodeProperties(data map[string]interface{}, props interface{}) error {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(dataJSON, props); err != nil {
+		return fmt.Errorf("invalid %v element: %w", data["type"], err)
+	}
+
+	extra := make(map[string]interface{})
+	for key, value := range data {
+		if key != "type" && !hasJSONField(reflect.TypeOf(props), key) {
+			extra[key] = value
+		}
+	}
+	if common := reflect.ValueOf(props).Elem().FieldByName("ElementProperties"); len(extra) > 0 && common.IsValid() {
+		common.Addr().Interface().(*ElementProperties).Extra = extra
+	}
+	return nil
+}
+
+// Name returns the name of the element, or an empty string if it has none.
+func Name(element interface{}) string {
+	if data, ok := element.(map[string]interface{}); ok {
+		name, _ := data["name"].(string)
+		return name
+	}
+	if common := commonProperties(element); common != nil {
+		return common.Name
+	}
+	return ""
+}
+
+// Children returns the nested elements of a composition.
+func Children(element interface{}) []interface{} {
+	switch e := element.(type) {
+	case *Composition:
+		if props, ok := e.Properties.(CompositionProperties); ok {
+			return props.Elements
+		}
+	case map[string]interface{}:
+		if children, ok := e["elements"].([]interface{}); ok {
+			return children
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for every element in the list and, depth-first, for their nested elements.
+func Walk(list []interface{}, fn func(element interface{})) {
+	for _, element := range list {
+		fn(element)
+		Walk(Children(element), fn)
+	}
+}
+
+// commonProperties returns the ElementProperties embedded in the element's properties.
+func commonProperties(element interface{}) *ElementProperties {
+	v := reflect.ValueOf(element)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	base := v.FieldByName("BaseElement")
+	if !base.IsValid() {
+		return nil
+	}
+
+	return elementProperties(base.FieldByName("Properties").Interface())
+}
+
+// HasProperty reports whether the element supports the property with the given JSON name.
+// Elements of an unknown type, represented as a map, support any property.
+func HasProperty(element interface{}, property string) bool {
+	if _, ok := element.(map[string]interface{}); ok {
+		return true
+	}
+
+	v := reflect.ValueOf(element)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	base := v.FieldByName("BaseElement")
+	if !base.IsValid() {
+		return false
+	}
+
+	propsType := reflect.TypeOf(base.FieldByName("Properties").Interface())
+	if propsType == nil {
+		return false
+	}
+	return hasJSONField(propsType, property)
+}
+
+// hasJSONField reports whether the struct type, including its embedded structs, has a field
+// with the given JSON name. Fields that are expanded by ToMap don't count.
+func hasJSONField(t reflect.Type, name string) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			if hasJSONField(field.Type, name) {
+				return true
+			}
+			continue
+		}
+
+		fieldName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if fieldName == name && fieldName != "-" && !shouldExpand(fieldName) {
+			return true
+		}
+	}
+	return false
+}
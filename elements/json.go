@@ -0,0 +1,80 @@
+package elements
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the element in the format of the API, as produced by ToMap.
+func (e BaseElement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.ToMap())
+}
+
+// UnmarshalJSON decodes an element of any type. Elements of an unknown type keep their
+// properties as a map.
+func (e *BaseElement) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "", e)
+}
+
+func (e *BaseElement) base() *BaseElement {
+	return e
+}
+
+func (v *Video) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "video", &v.BaseElement)
+}
+
+func (i *Image) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "image", &i.BaseElement)
+}
+
+func (t *Text) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "text", &t.BaseElement)
+}
+
+func (a *Audio) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "audio", &a.BaseElement)
+}
+
+func (s *Shape) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "shape", &s.BaseElement)
+}
+
+func (r *Rectangle) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "rectangle", &r.BaseElement)
+}
+
+func (e *Ellipse) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "ellipse", &e.BaseElement)
+}
+
+func (c *Composition) UnmarshalJSON(data []byte) error {
+	return unmarshalElement(data, "composition", &c.BaseElement)
+}
+
+// unmarshalElement decodes an element into target, which must be of the given type unless
+// elementType is empty.
+func unmarshalElement(data []byte, elementType string, target *BaseElement) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	actualType, _ := raw["type"].(string)
+	if elementType != "" && actualType != elementType {
+		return fmt.Errorf("cannot unmarshal %q element into %s element", actualType, elementType)
+	}
+
+	element, err := FromMap(raw)
+	if err != nil {
+		return err
+	}
+	if typed, ok := element.(interface{ base() *BaseElement }); ok {
+		*target = *typed.base()
+		return nil
+	}
+
+	delete(raw, "type")
+	*target = BaseElement{Type: actualType, Properties: raw}
+	return nil
+}
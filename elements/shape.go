@@ -6,37 +6,37 @@ type ShapeProperties struct {
 	ElementProperties
 
 	// The fill.
-	Fill ValueOrKeyframes[*properties.Fill] `json:"fill,omitempty"`
+	Fill properties.ValueOrKeyframes[*properties.Fill] `json:"fill,omitempty"`
 
 	// The fill color.
-	FillColor ValueOrKeyframes[interface{}] `json:"fill_color,omitempty"`
+	FillColor properties.ValueOrKeyframes[interface{}] `json:"fill_color,omitempty"`
 
 	// The fill mode.
-	FillMode ValueOrKeyframes[string] `json:"fill_mode,omitempty"`
+	FillMode properties.ValueOrKeyframes[string] `json:"fill_mode,omitempty"`
 
 	// The stroke.
-	Stroke ValueOrKeyframes[*properties.Stroke] `json:"stroke,omitempty"`
+	Stroke properties.ValueOrKeyframes[*properties.Stroke] `json:"stroke,omitempty"`
 
 	// The stroke color of the element.
-	StrokeColor ValueOrKeyframes[string] `json:"stroke_color,omitempty"`
+	StrokeColor properties.ValueOrKeyframes[string] `json:"stroke_color,omitempty"`
 
 	// The size of the stroke.
-	StrokeWidth ValueOrKeyframes[interface{}] `json:"stroke_width,omitempty"`
+	StrokeWidth properties.ValueOrKeyframes[interface{}] `json:"stroke_width,omitempty"`
 
 	// The stroke cap.
-	StrokeCap ValueOrKeyframes[properties.StrokeCap] `json:"stroke_cap,omitempty"`
+	StrokeCap properties.ValueOrKeyframes[properties.StrokeCap] `json:"stroke_cap,omitempty"`
 
 	// The stroke join.
-	StrokeJoin ValueOrKeyframes[properties.StrokeJoin] `json:"stroke_join,omitempty"`
+	StrokeJoin properties.ValueOrKeyframes[properties.StrokeJoin] `json:"stroke_join,omitempty"`
 
 	// The stroke start.
-	StrokeStart ValueOrKeyframes[interface{}] `json:"stroke_start,omitempty"`
+	StrokeStart properties.ValueOrKeyframes[interface{}] `json:"stroke_start,omitempty"`
 
 	// The stroke offset.
-	StrokeOffset ValueOrKeyframes[interface{}] `json:"stroke_offset,omitempty"`
+	StrokeOffset properties.ValueOrKeyframes[interface{}] `json:"stroke_offset,omitempty"`
 
 	// The path for the shape.
-	Path ValueOrKeyframes[string] `json:"path,omitempty"`
+	Path properties.ValueOrKeyframes[string] `json:"path,omitempty"`
 }
 
 type Shape struct {
@@ -56,7 +56,7 @@ type RectangleProperties struct {
 	ShapeProperties
 
 	// The border radius of the rectangle.
-	BorderRadius ValueOrKeyframes[interface{}] `json:"border_radius,omitempty"`
+	BorderRadius properties.ValueOrKeyframes[interface{}] `json:"border_radius,omitempty"`
 }
 
 type Rectangle struct {
@@ -15,25 +15,31 @@ type TextProperties struct {
 	FontFamily string `json:"font_family,omitempty"`
 
 	// The font size.
-	FontSize ValueOrKeyframes[interface{}] `json:"font_size,omitempty"`
+	FontSize properties.ValueOrKeyframes[interface{}] `json:"font_size,omitempty"`
 
 	// The font weight (100-900).
-	FontWeight ValueOrKeyframes[int] `json:"font_weight,omitempty"`
+	FontWeight properties.ValueOrKeyframes[int] `json:"font_weight,omitempty"`
 
 	// The font style (normal or italic).
 	FontStyle string `json:"font_style,omitempty"`
 
+	// The minimum font size when the font size is determined automatically.
+	FontSizeMinimum properties.ValueOrKeyframes[interface{}] `json:"font_size_minimum,omitempty"`
+
+	// The maximum font size when the font size is determined automatically.
+	FontSizeMaximum properties.ValueOrKeyframes[interface{}] `json:"font_size_maximum,omitempty"`
+
 	// Text transform.
 	TextTransform properties.TextTransform `json:"text_transform,omitempty"`
 
 	// Line height as a percentage of the font size.
-	LineHeight ValueOrKeyframes[interface{}] `json:"line_height,omitempty"`
+	LineHeight properties.ValueOrKeyframes[interface{}] `json:"line_height,omitempty"`
 
 	// Letter spacing.
-	LetterSpacing ValueOrKeyframes[interface{}] `json:"letter_spacing,omitempty"`
+	LetterSpacing properties.ValueOrKeyframes[interface{}] `json:"letter_spacing,omitempty"`
 
 	// The text color.
-	Color ValueOrKeyframes[string] `json:"color,omitempty"`
+	Color properties.ValueOrKeyframes[string] `json:"color,omitempty"`
 
 	// Text background.
 	TextBackground interface{} `json:"text_background,omitempty"` // *creatomate.TextBackground
@@ -41,29 +47,44 @@ type TextProperties struct {
 	// Background properties (expanded from TextBackground)
 	Background interface{} `json:"background,omitempty"` // *creatomate.TextBackground
 
+	// The color of the text background.
+	BackgroundColor properties.ValueOrKeyframes[string] `json:"background_color,omitempty"`
+
+	// The padding of the text background on the horizontal axis.
+	BackgroundXPadding properties.ValueOrKeyframes[interface{}] `json:"background_x_padding,omitempty"`
+
+	// The padding of the text background on the vertical axis.
+	BackgroundYPadding properties.ValueOrKeyframes[interface{}] `json:"background_y_padding,omitempty"`
+
+	// The border radius of the text background.
+	BackgroundBorderRadius properties.ValueOrKeyframes[interface{}] `json:"background_border_radius,omitempty"`
+
+	// Controls how the background of adjacent lines is aligned.
+	BackgroundAlignThreshold properties.ValueOrKeyframes[interface{}] `json:"background_align_threshold,omitempty"`
+
 	// The fill.
-	Fill ValueOrKeyframes[*properties.Fill] `json:"fill,omitempty"`
+	Fill properties.ValueOrKeyframes[*properties.Fill] `json:"fill,omitempty"`
 
 	// The fill color.
-	FillColor ValueOrKeyframes[interface{}] `json:"fill_color,omitempty"`
+	FillColor properties.ValueOrKeyframes[interface{}] `json:"fill_color,omitempty"`
 
 	// The fill mode.
-	FillMode ValueOrKeyframes[string] `json:"fill_mode,omitempty"`
+	FillMode properties.ValueOrKeyframes[string] `json:"fill_mode,omitempty"`
 
 	// The stroke.
-	Stroke ValueOrKeyframes[*properties.Stroke] `json:"stroke,omitempty"`
+	Stroke properties.ValueOrKeyframes[*properties.Stroke] `json:"stroke,omitempty"`
 
 	// The stroke color of the text.
-	StrokeColor ValueOrKeyframes[string] `json:"stroke_color,omitempty"`
+	StrokeColor properties.ValueOrKeyframes[string] `json:"stroke_color,omitempty"`
 
 	// The size of the stroke.
-	StrokeWidth ValueOrKeyframes[interface{}] `json:"stroke_width,omitempty"`
+	StrokeWidth properties.ValueOrKeyframes[interface{}] `json:"stroke_width,omitempty"`
 
 	// The stroke cap.
-	StrokeCap ValueOrKeyframes[properties.StrokeCap] `json:"stroke_cap,omitempty"`
+	StrokeCap properties.ValueOrKeyframes[properties.StrokeCap] `json:"stroke_cap,omitempty"`
 
 	// The stroke join.
-	StrokeJoin ValueOrKeyframes[properties.StrokeJoin] `json:"stroke_join,omitempty"`
+	StrokeJoin properties.ValueOrKeyframes[properties.StrokeJoin] `json:"stroke_join,omitempty"`
 
 	// Text flow direction.
 	FlowDirection properties.FlowDirection `json:"flow_direction,omitempty"`
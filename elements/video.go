@@ -36,22 +36,22 @@ type VideoProperties struct {
 	Fit properties.Fit `json:"fit,omitempty"`
 
 	// The stroke.
-	Stroke ValueOrKeyframes[*properties.Stroke] `json:"stroke,omitempty"`
+	Stroke properties.ValueOrKeyframes[*properties.Stroke] `json:"stroke,omitempty"`
 
 	// The stroke color of the element.
-	StrokeColor ValueOrKeyframes[string] `json:"stroke_color,omitempty"`
+	StrokeColor properties.ValueOrKeyframes[string] `json:"stroke_color,omitempty"`
 
 	// The size of the stroke.
-	StrokeWidth ValueOrKeyframes[interface{}] `json:"stroke_width,omitempty"`
+	StrokeWidth properties.ValueOrKeyframes[interface{}] `json:"stroke_width,omitempty"`
 
 	// The stroke cap.
-	StrokeCap ValueOrKeyframes[properties.StrokeCap] `json:"stroke_cap,omitempty"`
+	StrokeCap properties.ValueOrKeyframes[properties.StrokeCap] `json:"stroke_cap,omitempty"`
 
 	// The stroke join.
-	StrokeJoin ValueOrKeyframes[properties.StrokeJoin] `json:"stroke_join,omitempty"`
+	StrokeJoin properties.ValueOrKeyframes[properties.StrokeJoin] `json:"stroke_join,omitempty"`
 
 	// The border radius of the element.
-	BorderRadius ValueOrKeyframes[interface{}] `json:"border_radius,omitempty"`
+	BorderRadius properties.ValueOrKeyframes[interface{}] `json:"border_radius,omitempty"`
 }
 
 type Video struct {
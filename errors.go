@@ -1,16 +1,76 @@
 package creatomate
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// requestIDHeaders lists the response headers that may carry a request ID, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid", "Cf-Ray"}
 
 type CreatomateError struct {
 	Message string
+
+	// The HTTP status code of the response, or 0 if no response was received.
+	StatusCode int
+
+	// The headers of the response, if any.
+	Header http.Header
+
+	// The request ID reported by the API, if any.
+	RequestID string
+
+	// The raw response body, if any.
+	Body []byte
+
+	// The underlying error, such as a network error.
+	Cause error
 }
 
 func (e *CreatomateError) Error() string {
-	if e.Message != "" {
-		return e.Message
+	message := e.Message
+	if message == "" {
+		message = "Creatomate API error"
+		if e.StatusCode != 0 {
+			message = fmt.Sprintf("%s (status %d)", message, e.StatusCode)
+		}
+	}
+	if e.Cause != nil {
+		return message + ": " + e.Cause.Error()
+	}
+	return message
+}
+
+// Unwrap returns the underlying error.
+func (e *CreatomateError) Unwrap() error {
+	return e.Cause
+}
+
+// As allows errors.As to extract a *CreatomateError from any of the error types
+// that embed it, such as *BadRequestError.
+func (e *CreatomateError) As(target interface{}) bool {
+	if t, ok := target.(**CreatomateError); ok {
+		*t = e
+		return true
+	}
+	return false
+}
+
+// setResponse records the details of the HTTP response that caused the error.
+func (e *CreatomateError) setResponse(resp *http.Response, body []byte) {
+	e.StatusCode = resp.StatusCode
+	e.Header = resp.Header
+	e.Body = body
+	for _, name := range requestIDHeaders {
+		if requestID := resp.Header.Get(name); requestID != "" {
+			e.RequestID = requestID
+			break
+		}
 	}
-	return "Creatomate API error"
 }
 
 func NewCreatomateError(message string) *CreatomateError {
@@ -67,12 +127,80 @@ func NewConnectionError() *ConnectionError {
 	}
 }
 
+// newConnectionErrorWithCause creates a ConnectionError that wraps the underlying network error.
+func newConnectionErrorWithCause(cause error) *ConnectionError {
+	err := NewConnectionError()
+	err.Cause = cause
+	return err
+}
+
 type TimeoutError struct {
 	CreatomateError
+
+	// The last known state of the renders that hadn't finished in time. They keep running
+	// and can be followed with Client.Watch.
+	Renders []Render
 }
 
 func NewTimeoutError() *TimeoutError {
 	return &TimeoutError{
 		CreatomateError: CreatomateError{Message: "Timeout waiting for render to complete"},
 	}
-}
\ No newline at end of file
+}
+
+// NewTimeoutErrorWithRenders creates a TimeoutError for the renders that hadn't finished,
+// whose IDs are added to the message.
+func NewTimeoutErrorWithRenders(renders []Render) *TimeoutError {
+	err := NewTimeoutError()
+	err.Renders = renders
+	if ids := err.IDs(); len(ids) > 0 {
+		err.Message = fmt.Sprintf("%s: %s", err.Message, strings.Join(ids, ", "))
+	}
+	return err
+}
+
+// IDs returns the IDs of the renders that hadn't finished in time.
+func (e *TimeoutError) IDs() []string {
+	ids := make([]string, len(e.Renders))
+	for i, render := range e.Renders {
+		ids[i] = render.ID
+	}
+	return ids
+}
+
+// IsRetryable reports whether the request that caused err can safely be sent again:
+// connection errors, rate limiting and server errors.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var connectionError *ConnectionError
+	if errors.As(err, &connectionError) {
+		return true
+	}
+	var rateLimitError *RateLimitExceededError
+	if errors.As(err, &rateLimitError) {
+		return true
+	}
+	var apiError *CreatomateError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode == http.StatusRequestTimeout || apiError.StatusCode >= 500
+	}
+	return false
+}
+
+// IsTemporary reports whether err is caused by a condition that is expected to resolve itself,
+// such as a retryable API error, a network timeout or a render that took too long.
+func IsTemporary(err error) bool {
+	if IsRetryable(err) {
+		return true
+	}
+
+	var timeoutError *TimeoutError
+	if errors.As(err, &timeoutError) {
+		return true
+	}
+	var netError net.Error
+	return errors.As(err, &netError) && netError.Timeout()
+}
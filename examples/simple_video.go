@@ -39,16 +39,16 @@ func main() {
 			elements.NewText(elements.TextProperties{
 				ElementProperties: elements.ElementProperties{
 					Track:      intPtr(2),
-					Y:          "75%",
-					Width:      "100%",
-					Height:     "20%",
-					XPadding:   "5 vw",
-					YPadding:   "5 vh",
-					YAlignment: "100%",
+					Y:          creatomate.Static[any]("75%"),
+					Width:      creatomate.Static[any]("100%"),
+					Height:     creatomate.Static[any]("20%"),
+					XPadding:   creatomate.Static[any]("5 vw"),
+					YPadding:   creatomate.Static[any]("5 vh"),
+					YAlignment: creatomate.Static[any]("100%"),
 				},
 				Text:       "Hello from Creatomate Go!",
 				Font:       creatomate.NewFont("Open Sans", 700),
-				FillColor:  "#FFFFFF",
+				FillColor:  creatomate.Static[any]("#FFFFFF"),
 				Background: creatomate.NewTextBackground("rgba(0,0,0,0.7)", "20%", "10%", "5%", "0%"),
 			}),
 		},
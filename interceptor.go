@@ -0,0 +1,81 @@
+package creatomate
+
+import (
+	"net/http"
+	"time"
+)
+
+// Interceptor observes the API requests made by the client. Every attempt of a retried
+// request passes through the interceptors, in the order in which they were added.
+// Downloads of render output don't.
+type Interceptor interface {
+	// BeforeRequest is called right before the request is sent and may modify it, e.g. to add
+	// headers. Returning an error aborts the request with that error.
+	BeforeRequest(req *http.Request) error
+
+	// AfterResponse is called with every response that was received, including error
+	// responses, along with its body and the time the request took.
+	AfterResponse(req *http.Request, resp *http.Response, body []byte, latency time.Duration)
+
+	// OnError is called when no response was received, e.g. because the connection failed,
+	// the context ended while waiting for the rate limiter or a BeforeRequest hook returned an
+	// error. In the latter cases, the latency is the time spent before the request was aborted.
+	OnError(req *http.Request, err error, latency time.Duration)
+}
+
+// InterceptorFuncs implements Interceptor with optional functions, so that only the hooks
+// of interest need to be set.
+type InterceptorFuncs struct {
+	BeforeRequestFunc func(req *http.Request) error
+	AfterResponseFunc func(req *http.Request, resp *http.Response, body []byte, latency time.Duration)
+	OnErrorFunc       func(req *http.Request, err error, latency time.Duration)
+}
+
+func (f InterceptorFuncs) BeforeRequest(req *http.Request) error {
+	if f.BeforeRequestFunc == nil {
+		return nil
+	}
+	return f.BeforeRequestFunc(req)
+}
+
+func (f InterceptorFuncs) AfterResponse(req *http.Request, resp *http.Response, body []byte, latency time.Duration) {
+	if f.AfterResponseFunc != nil {
+		f.AfterResponseFunc(req, resp, body, latency)
+	}
+}
+
+func (f InterceptorFuncs) OnError(req *http.Request, err error, latency time.Duration) {
+	if f.OnErrorFunc != nil {
+		f.OnErrorFunc(req, err, latency)
+	}
+}
+
+// WithInterceptor adds an interceptor to the client. It can be passed multiple times.
+func WithInterceptor(interceptor Interceptor) Option {
+	return func(c *Client) {
+		if interceptor != nil {
+			c.interceptors = append(c.interceptors, interceptor)
+		}
+	}
+}
+
+func (c *Client) beforeRequest(req *http.Request) error {
+	for _, interceptor := range c.interceptors {
+		if err := interceptor.BeforeRequest(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) afterResponse(req *http.Request, resp *http.Response, body []byte, latency time.Duration) {
+	for _, interceptor := range c.interceptors {
+		interceptor.AfterResponse(req, resp, body, latency)
+	}
+}
+
+func (c *Client) onRequestError(req *http.Request, err error, latency time.Duration) {
+	for _, interceptor := range c.interceptors {
+		interceptor.OnError(req, err, latency)
+	}
+}
@@ -0,0 +1,87 @@
+package creatomate
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the source in the format of the API, as produced by ToMap.
+func (s Source) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToMap())
+}
+
+// UnmarshalJSON decodes a source like ParseSource.
+func (s *Source) UnmarshalJSON(data []byte) error {
+	source, err := ParseSource(data)
+	if err != nil {
+		return err
+	}
+	*s = *source
+	return nil
+}
+
+// MarshalJSON encodes the font as the font_* properties it expands into.
+func (f Font) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.ToMap())
+}
+
+// UnmarshalJSON decodes a font from font_* properties.
+func (f *Font) UnmarshalJSON(data []byte) error {
+	var props struct {
+		Family  string      `json:"font_family"`
+		Weight  *int        `json:"font_weight"`
+		Style   *string     `json:"font_style"`
+		Size    interface{} `json:"font_size"`
+		Minimum interface{} `json:"font_size_minimum"`
+		Maximum interface{} `json:"font_size_maximum"`
+	}
+	if err := json.Unmarshal(data, &props); err != nil {
+		return err
+	}
+	*f = Font(props)
+	return nil
+}
+
+// MarshalJSON encodes the text background as the background_* properties it expands into.
+func (tb TextBackground) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tb.ToMap())
+}
+
+// UnmarshalJSON decodes a text background from background_* properties.
+func (tb *TextBackground) UnmarshalJSON(data []byte) error {
+	var props struct {
+		Color          string      `json:"background_color"`
+		XPadding       interface{} `json:"background_x_padding"`
+		YPadding       interface{} `json:"background_y_padding"`
+		BorderRadius   interface{} `json:"background_border_radius"`
+		AlignThreshold interface{} `json:"background_align_threshold"`
+	}
+	if err := json.Unmarshal(data, &props); err != nil {
+		return err
+	}
+	*tb = TextBackground(props)
+	return nil
+}
+
+// MarshalJSON encodes the animation in the format of the API, as produced by ToMap.
+func (ts TextSlide) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ts.ToMap())
+}
+
+func (ts *TextSlide) UnmarshalJSON(data []byte) error {
+	var props struct {
+		Type string `json:"type"`
+		textSlideFields
+	}
+	if err := json.Unmarshal(data, &props); err != nil {
+		return err
+	}
+	if props.Type != "text-slide" {
+		return fmt.Errorf("cannot unmarshal %q animation into text-slide animation", props.Type)
+	}
+	*ts = TextSlide(props.textSlideFields)
+	return nil
+}
+
+// textSlideFields has the fields of TextSlide without its methods, to decode it.
+type textSlideFields TextSlide
@@ -24,4 +24,20 @@ func NewKeyframeWithEasing[T any](value T, time float64, easing properties.Easin
 		Value:  value,
 		Easing: easing,
 	}
-}
\ No newline at end of file
+}
+
+// Static returns a property with a static value. For properties that accept numbers as well as
+// strings, such as X, pass the type explicitly: Static[any]("50%").
+func Static[T any](value T) properties.ValueOrKeyframes[T] {
+	return properties.Static(value)
+}
+
+// Animated returns a property animated with the given keyframes, which should be in
+// chronological order.
+func Animated[T any](keyframes ...*Keyframe[T]) properties.ValueOrKeyframes[T] {
+	converted := make([]*properties.Keyframe[T], len(keyframes))
+	for i, keyframe := range keyframes {
+		converted[i] = (*properties.Keyframe[T])(keyframe)
+	}
+	return properties.Animated(converted...)
+}
@@ -0,0 +1,125 @@
+package creatomate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LogOptions configures the interceptor created by NewLogInterceptor.
+type LogOptions struct {
+	// Logs the request and response bodies. Render sources can be large, so this is off
+	// by default.
+	Bodies bool
+
+	// Logs the request headers. The Authorization header is always redacted.
+	Headers bool
+
+	// The level of successful requests. Error responses are logged at slog.LevelWarn and
+	// failed requests at slog.LevelError. Defaults to slog.LevelInfo.
+	Level slog.Level
+}
+
+// WithLogger logs every API request to logger with the default LogOptions.
+func WithLogger(logger *slog.Logger) Option {
+	return WithInterceptor(NewLogInterceptor(logger, LogOptions{}))
+}
+
+// NewLogInterceptor returns an Interceptor that logs the method, path, status, latency and
+// render IDs of every API request to logger.
+func NewLogInterceptor(logger *slog.Logger, options LogOptions) Interceptor {
+	return &logInterceptor{logger: logger, options: options}
+}
+
+type logInterceptor struct {
+	logger  *slog.Logger
+	options LogOptions
+}
+
+func (l *logInterceptor) BeforeRequest(req *http.Request) error {
+	return nil
+}
+
+func (l *logInterceptor) AfterResponse(req *http.Request, resp *http.Response, body []byte, latency time.Duration) {
+	level := l.options.Level
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+
+	attrs := l.requestAttrs(req, latency)
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if ids := renderIDs(req.URL.Path, body); len(ids) > 0 {
+		attrs = append(attrs, slog.Any("render_ids", ids))
+	}
+	if l.options.Bodies {
+		attrs = append(attrs, slog.String("response_body", string(body)))
+	}
+	l.logger.LogAttrs(req.Context(), level, "creatomate request", attrs...)
+}
+
+func (l *logInterceptor) OnError(req *http.Request, err error, latency time.Duration) {
+	attrs := l.requestAttrs(req, latency)
+	attrs = append(attrs, slog.String("error", err.Error()))
+	// The request context is usually done at this point, which must not suppress the log
+	l.logger.LogAttrs(context.Background(), slog.LevelError, "creatomate request failed", attrs...)
+}
+
+func (l *logInterceptor) requestAttrs(req *http.Request, latency time.Duration) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("latency", latency),
+	}
+	if req.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", req.URL.RawQuery))
+	}
+	if l.options.Headers {
+		header := req.Header.Clone()
+		if header.Get("Authorization") != "" {
+			header.Set("Authorization", "REDACTED")
+		}
+		attrs = append(attrs, slog.Any("header", header))
+	}
+	if l.options.Bodies && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			if len(data) > 0 {
+				attrs = append(attrs, slog.String("request_body", string(data)))
+			}
+		}
+	}
+	return attrs
+}
+
+// renderIDs returns the IDs of the renders in the response body of a render endpoint, which
+// holds either a single render or a list of them.
+func renderIDs(path string, body []byte) []string {
+	body = bytes.TrimSpace(body)
+	if !strings.Contains(path, "/renders") || len(body) == 0 {
+		return nil
+	}
+
+	var renders []struct {
+		ID string `json:"id"`
+	}
+	if body[0] == '{' {
+		body = append(append([]byte{'['}, body...), ']')
+	}
+	if err := json.Unmarshal(body, &renders); err != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(renders))
+	for _, render := range renders {
+		if render.ID != "" {
+			ids = append(ids, render.ID)
+		}
+	}
+	return ids
+}
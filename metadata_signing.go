@@ -0,0 +1,110 @@
+package creatomate
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const signedMetadataPrefix = "cm1."
+
+var (
+	// ErrMetadataNotSigned is returned by VerifyMetadata when the metadata is not a signed token.
+	ErrMetadataNotSigned = errors.New("metadata is not signed")
+
+	// ErrInvalidMetadataSignature is returned by VerifyMetadata when the signature doesn't match.
+	ErrInvalidMetadataSignature = errors.New("invalid metadata signature")
+)
+
+// SignedMetadata is the content of a token created by SignMetadata.
+type SignedMetadata struct {
+	// The metadata that was passed to SignMetadata.
+	Metadata string
+
+	// A random value that is unique for every token.
+	Nonce string
+
+	// The time at which the token was created.
+	IssuedAt time.Time
+}
+
+type signedMetadataPayload struct {
+	Metadata string `json:"m,omitempty"`
+	Nonce    string `json:"n"`
+	IssuedAt int64  `json:"t"`
+}
+
+// WithWebhookSecret makes StartRender replace RenderOptions.Metadata with a token signed with
+// secret whenever a WebhookURL is set, so that the webhook handler can verify the callback.
+func WithWebhookSecret(secret []byte) Option {
+	return func(c *Client) {
+		c.webhookSecret = secret
+	}
+}
+
+// SignMetadata wraps metadata in an HMAC-SHA256 signed token that also holds a nonce and the
+// time at which it was issued.
+func SignMetadata(secret []byte, metadata string, issuedAt time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	payloadJSON, err := json.Marshal(signedMetadataPayload{
+		Metadata: metadata,
+		Nonce:    base64.RawURLEncoding.EncodeToString(nonce),
+		IssuedAt: issuedAt.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	signed := signedMetadataPrefix + base64.RawURLEncoding.EncodeToString(payloadJSON)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(metadataSignature(secret, signed)), nil
+}
+
+// VerifyMetadata checks the signature of a token created by SignMetadata and returns its content.
+// Checking the age and uniqueness of the token is left to the caller.
+func VerifyMetadata(secret []byte, token string) (*SignedMetadata, error) {
+	if !strings.HasPrefix(token, signedMetadataPrefix) {
+		return nil, ErrMetadataNotSigned
+	}
+
+	separator := strings.LastIndexByte(token, '.')
+	if separator < len(signedMetadataPrefix) {
+		return nil, ErrMetadataNotSigned
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(token[separator+1:])
+	if err != nil || !hmac.Equal(signature, metadataSignature(secret, token[:separator])) {
+		return nil, ErrInvalidMetadataSignature
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(token[len(signedMetadataPrefix):separator])
+	if err != nil {
+		return nil, ErrInvalidMetadataSignature
+	}
+
+	var payload signedMetadataPayload
+	if err := json.Unmarshal(payloadJSON, &payload); err != nil || payload.Nonce == "" {
+		return nil, ErrInvalidMetadataSignature
+	}
+
+	return &SignedMetadata{
+		Metadata: payload.Metadata,
+		Nonce:    payload.Nonce,
+		IssuedAt: time.Unix(payload.IssuedAt, 0),
+	}, nil
+}
+
+func metadataSignature(secret []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,131 @@
+package creatomate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+)
+
+// Modifications builds the modifications of a template render.
+//
+//	mods := creatomate.NewModifications().
+//	    SetText("Title", "Hello").
+//	    SetSource("Background", "https://example.com/video.mp4").
+//	    Set("Title", "fill_color", "#ffffff")
+//
+//	renders, err := client.StartRender(ctx, creatomate.RenderOptions{
+//	    TemplateID:    templateID,
+//	    Modifications: mods.Map(),
+//	})
+type Modifications struct {
+	entries []modification
+}
+
+type modification struct {
+	element  string
+	property string
+	value    interface{}
+}
+
+func (m modification) key() string {
+	if m.property == "" {
+		return m.element
+	}
+	return m.element + "." + m.property
+}
+
+// ModificationError describes a modification that doesn't match the template.
+type ModificationError struct {
+	// The modification key, e.g. "Title.text".
+	Key    string
+	Reason string
+}
+
+func (e *ModificationError) Error() string {
+	return fmt.Sprintf("modification %q: %s", e.Key, e.Reason)
+}
+
+func NewModifications() *Modifications {
+	return &Modifications{}
+}
+
+// Set sets a property of the element with the given name. When property is empty, the value
+// is applied to the element's default property, such as the text of a text element.
+func (m *Modifications) Set(element, property string, value interface{}) *Modifications {
+	entry := modification{element: element, property: property, value: value}
+	for i := range m.entries {
+		if m.entries[i].key() == entry.key() {
+			m.entries[i] = entry
+			return m
+		}
+	}
+	m.entries = append(m.entries, entry)
+	return m
+}
+
+// SetText sets the text of a text element.
+func (m *Modifications) SetText(element, text string) *Modifications {
+	return m.Set(element, "text", text)
+}
+
+// SetSource sets the source URL of a video, image or audio element.
+func (m *Modifications) SetSource(element, source string) *Modifications {
+	return m.Set(element, "source", source)
+}
+
+// Map returns the modifications in the format of RenderOptions.Modifications.
+func (m *Modifications) Map() map[string]interface{} {
+	result := make(map[string]interface{}, len(m.entries))
+	for _, entry := range m.entries {
+		result[entry.key()] = entry.value
+	}
+	return result
+}
+
+// Validate checks that every modification refers to an element of the source by name,
+// and that the element supports the modified property. All problems are returned together.
+func (m *Modifications) Validate(source *Source) error {
+	named := make(map[string][]interface{})
+	if source != nil {
+		elements.Walk(source.Properties.Elements, func(element interface{}) {
+			if name := elements.Name(element); name != "" {
+				named[name] = append(named[name], element)
+			}
+		})
+	}
+
+	var errs []error
+	for _, entry := range m.entries {
+		matches, ok := named[entry.element]
+		if !ok {
+			errs = append(errs, &ModificationError{Key: entry.key(), Reason: fmt.Sprintf("unknown element %q", entry.element)})
+			continue
+		}
+		if entry.property == "" {
+			continue
+		}
+
+		supported := false
+		for _, element := range matches {
+			if elements.HasProperty(element, entry.property) {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			errs = append(errs, &ModificationError{Key: entry.key(), Reason: fmt.Sprintf("unknown property %q", entry.property)})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateModifications fetches the template and validates the modifications against its source.
+func (c *Client) ValidateModifications(ctx context.Context, templateID string, modifications *Modifications) error {
+	template, err := c.GetTemplate(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	return modifications.Validate(template.Source)
+}
@@ -0,0 +1,137 @@
+package creatomate
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// PollStrategy decides how long Render and Watch wait between polling rounds.
+type PollStrategy interface {
+	// Interval returns the delay before the given round, starting at 1. Pending holds the
+	// last known state of the renders that are still being polled. Zero or a negative
+	// delay is replaced with the default interval of 2 seconds.
+	Interval(round int, pending []Render) time.Duration
+}
+
+// FixedInterval polls at a constant interval. Zero polls at the default interval.
+type FixedInterval time.Duration
+
+func (f FixedInterval) Interval(round int, pending []Render) time.Duration {
+	return orDefaultInterval(time.Duration(f))
+}
+
+// ExponentialInterval polls at an interval that grows by Multiplier every round, up to Max.
+// Without an Initial interval, it starts at the default interval.
+type ExponentialInterval struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+func (e ExponentialInterval) Interval(round int, pending []Render) time.Duration {
+	multiplier := e.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	interval := time.Duration(float64(e.Initial) * math.Pow(multiplier, float64(round-1)))
+	if e.Max > 0 && (interval > e.Max || interval < 0) {
+		interval = e.Max
+	}
+	return orDefaultInterval(interval)
+}
+
+// DurationInterval polls at an interval proportional to the longest output duration of the
+// pending renders, as longer videos take longer to render.
+type DurationInterval struct {
+	// The interval per second of output, e.g. 0.5 polls a 60 second video every 30 seconds.
+	Factor float64
+
+	// The bounds of the interval. Min is also used while the duration is unknown; when
+	// that leaves no interval, the default interval of 2 seconds is used.
+	Min time.Duration
+	Max time.Duration
+}
+
+func (d DurationInterval) Interval(round int, pending []Render) time.Duration {
+	longest := 0.0
+	for _, render := range pending {
+		longest = math.Max(longest, render.Duration)
+	}
+
+	interval := time.Duration(longest * d.Factor * float64(time.Second))
+	if interval < d.Min {
+		interval = d.Min
+	}
+	if d.Max > 0 && interval > d.Max {
+		interval = d.Max
+	}
+	return orDefaultInterval(interval)
+}
+
+// orDefaultInterval replaces an interval that would poll in a tight loop with the default.
+func orDefaultInterval(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return defaultPollInterval
+	}
+	return interval
+}
+
+// WithPollStrategy sets the strategy that Render and Watch use to space out polling rounds.
+// Defaults to FixedInterval(2 * time.Second).
+func WithPollStrategy(strategy PollStrategy) Option {
+	return func(c *Client) {
+		if strategy != nil {
+			c.pollStrategy = strategy
+		}
+	}
+}
+
+// WithPollBudget limits the number of renders fetched per polling round. Renders are polled
+// in turn, so with many renders each one is fetched less often. Zero means no limit.
+//
+// The budget is shared by every call of Render and Watch and every item of a RenderBatch on
+// the client. A round lasts one interval of the PollStrategy; calls that find the budget
+// used up wait for the next round.
+func WithPollBudget(requestsPerRound int) Option {
+	return func(c *Client) {
+		c.pollBudget = nil
+		if requestsPerRound > 0 {
+			c.pollBudget = &roundBudget{limit: requestsPerRound}
+		}
+	}
+}
+
+// roundBudget limits the number of fetches per polling round across the watches of a client.
+// A round starts with the first fetch after the previous round ended.
+type roundBudget struct {
+	mu        sync.Mutex
+	limit     int
+	used      int
+	roundEnds time.Time
+}
+
+// take claims up to n fetches in the current round, which lasts interval if it starts now.
+// It returns the number of fetches granted and when the round ends. A nil budget grants
+// every fetch.
+func (b *roundBudget) take(n int, interval time.Duration) (int, time.Time) {
+	if b == nil {
+		return n, time.Time{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if !now.Before(b.roundEnds) {
+		b.used = 0
+		b.roundEnds = now.Add(interval)
+	}
+	granted := b.limit - b.used
+	if granted > n {
+		granted = n
+	}
+	b.used += granted
+	return granted, b.roundEnds
+}
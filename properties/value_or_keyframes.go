@@ -0,0 +1,206 @@
+package properties
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ValueOrKeyframes holds a property that is either a static value or animated with keyframes.
+// Create one with Static or Animated; the zero value is unset and left out of the JSON.
+// Decoded values that don't fit T, and explicit nulls, are kept as raw JSON.
+type ValueOrKeyframes[T any] struct {
+	value     T
+	keyframes []*Keyframe[T]
+	static    bool
+	raw       json.RawMessage
+}
+
+// Static returns a property with a static value.
+func Static[T any](value T) ValueOrKeyframes[T] {
+	return ValueOrKeyframes[T]{value: value, static: true}
+}
+
+// Animated returns a property animated with the given keyframes, which should be in
+// chronological order. Without keyframes, the property is unset.
+func Animated[T any](keyframes ...*Keyframe[T]) ValueOrKeyframes[T] {
+	return ValueOrKeyframes[T]{keyframes: keyframes}
+}
+
+// IsZero reports whether the property is unset.
+func (v ValueOrKeyframes[T]) IsZero() bool {
+	return !v.static && len(v.keyframes) == 0 && v.raw == nil
+}
+
+// IsAnimated reports whether the property is animated with keyframes.
+func (v ValueOrKeyframes[T]) IsAnimated() bool {
+	return len(v.keyframes) > 0
+}
+
+// Value returns the static value of the property, and false if it is unset or animated.
+func (v ValueOrKeyframes[T]) Value() (T, bool) {
+	return v.value, v.static
+}
+
+// Keyframes returns the keyframes of the property, or nil if it isn't animated.
+func (v ValueOrKeyframes[T]) Keyframes() []*Keyframe[T] {
+	return v.keyframes
+}
+
+// Raw returns the JSON of a decoded value that doesn't fit T, such as "bold" for a font
+// weight, or null. It returns nil for any other property.
+func (v ValueOrKeyframes[T]) Raw() json.RawMessage {
+	return v.raw
+}
+
+// Interface returns the static value, the keyframes as an []interface{} or the decoded raw
+// JSON, or nil if the property is unset.
+func (v ValueOrKeyframes[T]) Interface() interface{} {
+	if v.static {
+		return v.value
+	}
+	if v.raw != nil {
+		var value interface{}
+		json.Unmarshal(v.raw, &value)
+		return value
+	}
+	if len(v.keyframes) == 0 {
+		return nil
+	}
+	result := make([]interface{}, len(v.keyframes))
+	for i, keyframe := range v.keyframes {
+		result[i] = keyframe
+	}
+	return result
+}
+
+// Validate checks that the keyframes are set and in chronological order.
+func (v ValueOrKeyframes[T]) Validate() error {
+	for i, keyframe := range v.keyframes {
+		if keyframe == nil {
+			return fmt.Errorf("keyframe %d is nil", i)
+		}
+		if i > 0 && v.keyframes[i-1] != nil && keyframe.Time < v.keyframes[i-1].Time {
+			return fmt.Errorf("keyframe %d at %gs comes before keyframe %d at %gs", i, keyframe.Time, i-1, v.keyframes[i-1].Time)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the static value, the keyframes as an array, or the raw JSON.
+func (v ValueOrKeyframes[T]) MarshalJSON() ([]byte, error) {
+	if v.IsZero() {
+		return []byte("null"), nil
+	}
+	if v.raw != nil {
+		return v.raw, nil
+	}
+	if v.static {
+		return json.Marshal(v.value)
+	}
+	return json.Marshal(v.keyframes)
+}
+
+// UnmarshalJSON decodes an array of objects with a time and a value as keyframes, and
+// anything else as a static value. Numeric properties also accept numbers in strings, such
+// as "700", which the API accepts as well; they are encoded as numbers again. Values that
+// don't fit T, such as "2 vmin" for a float64, and null are kept as raw JSON, so that they
+// are encoded as they are.
+func (v *ValueOrKeyframes[T]) UnmarshalJSON(data []byte) error {
+	if err := v.decode(data); err != nil {
+		*v = ValueOrKeyframes[T]{raw: append(json.RawMessage(nil), bytes.TrimSpace(data)...)}
+	}
+	return nil
+}
+
+// decode decodes keyframes or a static value of type T.
+func (v *ValueOrKeyframes[T]) decode(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return errors.New("null value")
+	}
+	if isKeyframeArray(data) {
+		var raw []struct {
+			Time   float64         `json:"time"`
+			Value  json.RawMessage `json:"value"`
+			Easing Easing          `json:"easing"`
+		}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		keyframes := make([]*Keyframe[T], len(raw))
+		for i, keyframe := range raw {
+			value, err := decodeValue[T](keyframe.Value)
+			if err != nil {
+				return err
+			}
+			keyframes[i] = &Keyframe[T]{Time: keyframe.Time, Value: value, Easing: keyframe.Easing}
+		}
+		*v = Animated(keyframes...)
+		return nil
+	}
+
+	value, err := decodeValue[T](data)
+	if err != nil {
+		return err
+	}
+	*v = Static(value)
+	return nil
+}
+
+// decodeValue decodes a value of type T, accepting a number in a string if T is numeric.
+func decodeValue[T any](data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	if err == nil || !isNumeric(reflect.TypeOf(value)) {
+		return value, err
+	}
+
+	var s string
+	if json.Unmarshal(data, &s) != nil {
+		return value, err
+	}
+	// A null in the string would decode to zero without an error
+	number := strings.TrimSpace(s)
+	if number == "null" || json.Unmarshal([]byte(number), &value) != nil {
+		return value, fmt.Errorf("json: cannot unmarshal string %q into Go value of type %T", s, value)
+	}
+	return value, nil
+}
+
+func isNumeric(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isKeyframeArray reports whether data is a non-empty array of objects that only have the
+// properties of a keyframe, including a time and a value.
+func isKeyframeArray(data []byte) bool {
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil || len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		_, hasTime := item["time"]
+		_, hasValue := item["value"]
+		if !hasTime || !hasValue {
+			return false
+		}
+		for key := range item {
+			if key != "time" && key != "value" && key != "easing" {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+package creatomate
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Routes that can be passed to WithRateLimit.
+const (
+	RouteStartRender  = "POST /renders"
+	RouteFetchRender  = "GET /renders/{id}"
+	RouteListRenders  = "GET /renders"
+	RouteCancelRender = "POST /renders/{id}/cancel"
+	RouteDeleteRender = "DELETE /renders/{id}"
+
+	// RouteDefault applies to every request that has no limiter of its own.
+	RouteDefault = "*"
+)
+
+// RateLimiter is a token bucket limiter that is safe for concurrent use. Besides its configured
+// rate, it follows the rate limit headers of the API: it slows down when the remaining quota
+// runs low and pauses until the quota resets or Retry-After has passed.
+type RateLimiter struct {
+	mu          sync.Mutex
+	maxRate     float64
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a limiter that allows perSecond requests on average, with bursts of
+// up to burst requests.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		maxRate: perSecond,
+		rate:    perSecond,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+}
+
+// Wait blocks until a request may be made or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Rate returns the number of requests per second currently allowed.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// reserve takes a token if one is available, or returns how long to wait for one.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	if l.rate <= 0 {
+		return time.Second
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+}
+
+// observe adjusts the limiter to the rate limit headers of a response.
+func (l *RateLimiter) observe(resp *http.Response, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.tokens = 0
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now); retryAfter > 0 {
+			l.pause(now.Add(retryAfter))
+		}
+	}
+
+	// Without quota headers, go back to the configured rate, as an earlier lowered rate
+	// would otherwise stick for good
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		l.rate = l.maxRate
+		return
+	}
+	reset := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now)
+	if reset <= 0 {
+		l.rate = l.maxRate
+		return
+	}
+
+	if remaining <= 0 {
+		l.tokens = 0
+		l.pause(now.Add(reset))
+		return
+	}
+
+	// Spread the remaining quota over the time left in the window
+	l.rate = math.Min(l.maxRate, float64(remaining)/reset.Seconds())
+	l.tokens = math.Min(l.tokens, float64(remaining))
+}
+
+func (l *RateLimiter) pause(until time.Time) {
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header, which holds either the number of
+// seconds until the window resets or the Unix time at which it resets.
+func parseRateLimitReset(value string, now time.Time) time.Duration {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	if seconds > 1e9 {
+		return time.Unix(int64(seconds), 0).Sub(now)
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// WithRateLimit throttles requests to the given route, e.g. RouteStartRender, to perSecond
+// requests on average with bursts of up to burst requests.
+func WithRateLimit(route string, perSecond float64, burst int) Option {
+	return WithRateLimiter(route, NewRateLimiter(perSecond, burst))
+}
+
+// WithRateLimiter throttles requests to the given route using limiter, which may be shared
+// with other clients.
+func WithRateLimiter(route string, limiter *RateLimiter) Option {
+	return func(c *Client) {
+		if c.rateLimiters == nil {
+			c.rateLimiters = make(map[string]*RateLimiter)
+		}
+		c.rateLimiters[route] = limiter
+	}
+}
+
+// rateLimiter returns the limiter for the request, or nil if it isn't limited.
+func (c *Client) rateLimiter(method, path string) *RateLimiter {
+	if limiter, ok := c.rateLimiters[RouteOf(method, path)]; ok {
+		return limiter
+	}
+	return c.rateLimiters[RouteDefault]
+}
+
+// RouteOf returns the route of a request, replacing IDs in the path with "{id}",
+// e.g. "GET /renders/{id}", so that it can be compared with RouteFetchRender and the like.
+func RouteOf(method, path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i += 2 {
+		segments[i] = "{id}"
+	}
+	return method + " /" + strings.Join(segments, "/")
+}
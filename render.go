@@ -11,6 +11,14 @@ const (
 	RenderStatusFailed       RenderStatus = "failed"
 )
 
+// InProgress reports whether a render with this status hasn't finished yet.
+func (s RenderStatus) InProgress() bool {
+	return s == RenderStatusPlanned ||
+		s == RenderStatusWaiting ||
+		s == RenderStatusTranscribing ||
+		s == RenderStatusRendering
+}
+
 type Render struct {
 	ID            string                 `json:"id"`
 	Status        RenderStatus           `json:"status"`
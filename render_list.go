@@ -0,0 +1,142 @@
+package creatomate
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultListPageSize = 100
+
+type ListRendersOptions struct {
+	// Only return renders with one of these statuses.
+	Statuses []RenderStatus
+
+	// Only return renders of this template.
+	TemplateID string
+
+	// Only return renders that have all of these tags.
+	Tags []string
+
+	// Only return renders created at or after this time.
+	CreatedAfter time.Time
+
+	// Only return renders created before this time.
+	CreatedBefore time.Time
+
+	// The number of renders fetched per request. Defaults to 100.
+	PageSize int
+}
+
+// query returns the query parameters for the given page.
+func (o ListRendersOptions) query(page int) url.Values {
+	query := url.Values{}
+	if len(o.Statuses) > 0 {
+		statuses := make([]string, len(o.Statuses))
+		for i, status := range o.Statuses {
+			statuses[i] = string(status)
+		}
+		query.Set("status", strings.Join(statuses, ","))
+	}
+	if o.TemplateID != "" {
+		query.Set("template_id", o.TemplateID)
+	}
+	if len(o.Tags) > 0 {
+		query.Set("tags", strings.Join(o.Tags, ","))
+	}
+	if !o.CreatedAfter.IsZero() {
+		query.Set("created_after", o.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if !o.CreatedBefore.IsZero() {
+		query.Set("created_before", o.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+	query.Set("limit", strconv.Itoa(o.pageSize()))
+	query.Set("page", strconv.Itoa(page))
+	return query
+}
+
+func (o ListRendersOptions) pageSize() int {
+	if o.PageSize > 0 {
+		return o.PageSize
+	}
+	return defaultListPageSize
+}
+
+// RenderIterator iterates over the renders returned by ListRenders, fetching pages as needed.
+//
+//	it := client.ListRenders(ctx, creatomate.ListRendersOptions{TemplateID: id})
+//	for it.Next() {
+//	    render := it.Render()
+//	}
+//	if err := it.Err(); err != nil {
+//	    ...
+//	}
+type RenderIterator struct {
+	ctx     context.Context
+	client  *Client
+	options ListRendersOptions
+
+	page    int
+	renders []Render
+	current Render
+	done    bool
+	err     error
+}
+
+// ListRenders returns an iterator over the renders that match the options.
+func (c *Client) ListRenders(ctx context.Context, options ListRendersOptions) *RenderIterator {
+	return &RenderIterator{
+		ctx:     ctx,
+		client:  c,
+		options: options,
+	}
+}
+
+// Next advances to the next render. It returns false when there are no more renders
+// or an error occurred.
+func (it *RenderIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.renders) == 0 {
+		if it.done {
+			return false
+		}
+		if !it.fetchPage() {
+			return false
+		}
+	}
+
+	it.current = it.renders[0]
+	it.renders = it.renders[1:]
+	return true
+}
+
+// Render returns the render at the current position.
+func (it *RenderIterator) Render() Render {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RenderIterator) Err() error {
+	return it.err
+}
+
+// fetchPage fetches the next page, returning false if it is empty or failed.
+func (it *RenderIterator) fetchPage() bool {
+	it.page++
+
+	var renders []Render
+	path := "/renders?" + it.options.query(it.page).Encode()
+	if err := it.client.httpRequest(it.ctx, "GET", path, nil, &renders); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.renders = renders
+	it.done = len(renders) < it.options.pageSize()
+	return len(renders) > 0
+}
@@ -1,5 +1,7 @@
 package creatomate
 
+import "time"
+
 type RenderOutputFormat string
 
 const (
@@ -21,4 +23,15 @@ type RenderOptions struct {
 	Modifications map[string]interface{} `json:"modifications,omitempty"`
 	WebhookURL    string                 `json:"webhook_url,omitempty"`
 	Metadata      string                 `json:"metadata,omitempty"`
-}
\ No newline at end of file
+
+	// Cancels the renders that are still unfinished when Client.Render or Client.RenderBatch
+	// gives up waiting because the context is done or the timeout expired, so they don't use
+	// up credits.
+	CancelOnAbandon bool `json:"-"`
+
+	// Returns how long to wait for a single render, counted from when it was started. Renders
+	// that take longer are given up on while waiting for the others continues; they are
+	// reported by the TimeoutError returned at the end. Zero means the render may take as long
+	// as the overall timeout.
+	PerRenderTimeout func(render Render) time.Duration `json:"-"`
+}
@@ -0,0 +1,21 @@
+package creatomate
+
+import (
+	"context"
+	"time"
+)
+
+// Renderer is the part of Client that starts and tracks renders. Depend on it instead of
+// *Client to replace the client in tests, e.g. with creatomatetest.MemoryRenderer.
+type Renderer interface {
+	// Render starts a new render and awaits its completion.
+	Render(ctx context.Context, options RenderOptions, timeout time.Duration) ([]Render, error)
+
+	// StartRender starts a render, but doesn't wait for it to finish.
+	StartRender(ctx context.Context, options RenderOptions) ([]Render, error)
+
+	// FetchRender fetches the status of the render.
+	FetchRender(ctx context.Context, id string) (*Render, error)
+}
+
+var _ Renderer = (*Client)(nil)
@@ -0,0 +1,132 @@
+package creatomate
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed API requests are retried.
+type RetryPolicy struct {
+	// The maximum number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+
+	// The delay before the first retry.
+	InitialBackoff time.Duration
+
+	// The upper bound for any single delay, including delays requested through Retry-After.
+	MaxBackoff time.Duration
+
+	// The factor by which the delay grows after every attempt.
+	Multiplier float64
+
+	// The fraction (0-1) of each delay that is randomized.
+	Jitter float64
+
+	// When set to true, non-idempotent requests such as StartRender are retried as well.
+	// This may start the same render twice if the first request reached the API. Requests
+	// rejected with status 429 are always retried, since the API didn't process them.
+	RetryNonIdempotent bool
+
+	// Called before every retry.
+	OnRetry func(RetryAttempt)
+}
+
+// RetryAttempt describes a retry that is about to happen.
+type RetryAttempt struct {
+	Method string
+	Path   string
+
+	// The number of the attempt that failed, starting at 1.
+	Attempt int
+
+	// The error returned by the failed attempt.
+	Err error
+
+	// The delay before the next attempt.
+	Delay time.Duration
+}
+
+// DefaultRetryPolicy returns a policy that retries idempotent and rate limited requests up
+// to 4 times.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy enables retries for failed requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// maxAttempts returns the number of attempts allowed for the given method after an attempt
+// failed with err. Rate limited requests are retried whatever the method.
+func (p RetryPolicy) maxAttempts(method string, err error) int {
+	if p.MaxAttempts < 2 {
+		return 1
+	}
+	var rateLimitError *RateLimitExceededError
+	if !isIdempotent(method) && !p.RetryNonIdempotent && !errors.As(err, &rateLimitError) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay after the given failed attempt, preferring retryAfter unless it
+// is negative.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := retryAfter
+	if delay < 0 {
+		multiplier := p.Multiplier
+		if multiplier < 1 {
+			multiplier = 1
+		}
+		delay = time.Duration(float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1)))
+		if p.Jitter > 0 {
+			delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header, which holds either seconds or an HTTP date.
+// It returns -1 if the header is missing or malformed.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return -1
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay
+		}
+		return 0
+	}
+	return -1
+}
@@ -6,8 +6,6 @@ import (
 	"github.com/Lakeshore-Labs/creatomate-go/properties"
 )
 
-type ValueOrKeyframes[T any] interface{}
-
 type SourceProperties struct {
 	// The output format of the render, which can be jpg, png, gif, or mp4.
 	OutputFormat properties.OutputFormat `json:"output_format"`
@@ -43,28 +41,28 @@ type SourceProperties struct {
 	EmojiStyle properties.EmojiStyle `json:"emoji_style,omitempty"`
 
 	// The background fill.
-	Fill ValueOrKeyframes[*properties.Fill] `json:"fill,omitempty"`
+	Fill properties.ValueOrKeyframes[*properties.Fill] `json:"fill,omitempty"`
 
 	// The background fill color.
-	FillColor ValueOrKeyframes[string] `json:"fill_color,omitempty"`
+	FillColor properties.ValueOrKeyframes[string] `json:"fill_color,omitempty"`
 
 	// The fill method used: solid, linear, and radial.
-	FillMode ValueOrKeyframes[string] `json:"fill_mode,omitempty"`
+	FillMode properties.ValueOrKeyframes[string] `json:"fill_mode,omitempty"`
 
 	// The start position of the gradient on the x-axis.
-	FillX0 ValueOrKeyframes[interface{}] `json:"fill_x0,omitempty"`
+	FillX0 properties.ValueOrKeyframes[interface{}] `json:"fill_x0,omitempty"`
 
 	// The start position of the gradient on the y-axis.
-	FillY0 ValueOrKeyframes[interface{}] `json:"fill_y0,omitempty"`
+	FillY0 properties.ValueOrKeyframes[interface{}] `json:"fill_y0,omitempty"`
 
 	// The end position of the gradient on the x-axis.
-	FillX1 ValueOrKeyframes[interface{}] `json:"fill_x1,omitempty"`
+	FillX1 properties.ValueOrKeyframes[interface{}] `json:"fill_x1,omitempty"`
 
 	// The end position of the gradient on the y-axis.
-	FillY1 ValueOrKeyframes[interface{}] `json:"fill_y1,omitempty"`
+	FillY1 properties.ValueOrKeyframes[interface{}] `json:"fill_y1,omitempty"`
 
 	// The radius of the radial gradient.
-	FillRadius ValueOrKeyframes[interface{}] `json:"fill_radius,omitempty"`
+	FillRadius properties.ValueOrKeyframes[interface{}] `json:"fill_radius,omitempty"`
 
 	// Custom fonts array.
 	Fonts []properties.FontDefinition `json:"fonts,omitempty"`
@@ -0,0 +1,315 @@
+package creatomate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/Lakeshore-Labs/creatomate-go/animations"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+	"github.com/Lakeshore-Labs/creatomate-go/properties"
+)
+
+// ParseSource parses the JSON of a source, e.g. as exported from the Creatomate editor, into
+// a Source. Elements become the type named by their "type" property, keyframe arrays become
+// animated ValueOrKeyframes, font_* and background_* properties of text elements become a
+// Font and a TextBackground, and animations become animation structs, using Enter, Exit and
+// Transition where ToMap would place them. Element properties without a field of their own,
+// such as locked and dynamic, are kept in ElementProperties.Extra, and values that don't fit
+// the type of their field, such as "2 vmin" for BlurRadius, are kept as raw JSON in the
+// ValueOrKeyframes. Elements and animations that can't be represented without loss are kept
+// as they are in the JSON. Properties of the source itself that SourceProperties has no field
+// for are dropped.
+func ParseSource(data []byte) (*Source, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+	return sourceFromMap(raw)
+}
+
+// sourceFromMap converts the JSON representation of a source, as produced by ToMap,
+// back into a Source with typed elements.
+func sourceFromMap(data map[string]interface{}) (*Source, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var props SourceProperties
+	if err := json.Unmarshal(dataJSON, &props); err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+
+	rawElements := props.Elements
+	props.Elements, err = elements.FromMaps(rawElements)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+	foldElements(props.Elements, rawElements)
+	return NewSource(props), nil
+}
+
+// foldElements folds the properties of the decoded elements, using their JSON representation
+// for the properties that the element types don't have fields for.
+func foldElements(list, raw []interface{}) {
+	for i, element := range list {
+		base := baseElement(element)
+		data, ok := raw[i].(map[string]interface{})
+		if base == nil || !ok {
+			continue
+		}
+
+		props := reflect.New(reflect.TypeOf(base.Properties)).Elem()
+		props.Set(reflect.ValueOf(base.Properties))
+
+		if text, ok := props.Addr().Interface().(*elements.TextProperties); ok {
+			foldFont(text, data)
+			foldTextBackground(text, data)
+		}
+		if common := props.FieldByName("ElementProperties"); common.IsValid() {
+			foldAnimations(common.Addr().Interface().(*elements.ElementProperties), data)
+		}
+
+		base.Properties = props.Interface()
+		if rawChildren, ok := data["elements"].([]interface{}); ok {
+			foldElements(elements.Children(element), rawChildren)
+		}
+	}
+}
+
+// baseElement returns the BaseElement embedded in an element, or nil for elements of an
+// unknown type.
+func baseElement(element interface{}) *elements.BaseElement {
+	v := reflect.ValueOf(element)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	base := v.Elem().FieldByName("BaseElement")
+	if !base.IsValid() || base.Type() != reflect.TypeOf(elements.BaseElement{}) {
+		return nil
+	}
+	return base.Addr().Interface().(*elements.BaseElement)
+}
+
+// foldFont moves the font_* properties of a text element into a Font.
+func foldFont(props *elements.TextProperties, data map[string]interface{}) {
+	family, ok := data["font_family"].(string)
+	if !ok || props.Font != nil {
+		return
+	}
+
+	font := &Font{
+		Family:  family,
+		Size:    data["font_size"],
+		Minimum: data["font_size_minimum"],
+		Maximum: data["font_size_maximum"],
+	}
+	if weight, ok := data["font_weight"]; ok {
+		number, ok := weight.(float64)
+		if !ok || number != math.Trunc(number) {
+			return
+		}
+		font.Weight = intPtr(int(number))
+	}
+	if style, ok := data["font_style"]; ok {
+		value, ok := style.(string)
+		if !ok {
+			return
+		}
+		font.Style = &value
+	}
+	for _, value := range []interface{}{font.Size, font.Minimum, font.Maximum} {
+		if _, ok := value.([]interface{}); ok {
+			// Animated font sizes stay in the font_* fields
+			return
+		}
+	}
+
+	props.Font = font
+	props.FontFamily = ""
+	props.FontWeight = properties.ValueOrKeyframes[int]{}
+	props.FontStyle = ""
+	props.FontSize = properties.ValueOrKeyframes[interface{}]{}
+	props.FontSizeMinimum = properties.ValueOrKeyframes[interface{}]{}
+	props.FontSizeMaximum = properties.ValueOrKeyframes[interface{}]{}
+}
+
+// foldTextBackground moves the background_* properties of a text element into a
+// TextBackground.
+func foldTextBackground(props *elements.TextProperties, data map[string]interface{}) {
+	color, ok := data["background_color"].(string)
+	if !ok || props.Background != nil || props.TextBackground != nil {
+		return
+	}
+	background := &TextBackground{
+		Color:          color,
+		XPadding:       data["background_x_padding"],
+		YPadding:       data["background_y_padding"],
+		BorderRadius:   data["background_border_radius"],
+		AlignThreshold: data["background_align_threshold"],
+	}
+	for _, value := range []interface{}{background.XPadding, background.YPadding, background.BorderRadius, background.AlignThreshold} {
+		if _, ok := value.([]interface{}); ok {
+			// Animated backgrounds stay in the background_* fields
+			return
+		}
+	}
+
+	props.Background = background
+	props.BackgroundColor = properties.ValueOrKeyframes[string]{}
+	props.BackgroundXPadding = properties.ValueOrKeyframes[interface{}]{}
+	props.BackgroundYPadding = properties.ValueOrKeyframes[interface{}]{}
+	props.BackgroundBorderRadius = properties.ValueOrKeyframes[interface{}]{}
+	props.BackgroundAlignThreshold = properties.ValueOrKeyframes[interface{}]{}
+}
+
+// foldAnimations converts the animations of an element into animation structs. As ToMap
+// writes the transition first, then the enter animation, the other animations and finally
+// the exit animation, only animations in those positions become Transition, Enter and Exit.
+func foldAnimations(props *elements.ElementProperties, data map[string]interface{}) {
+	list, ok := data["animations"].([]interface{})
+	if !ok || props.Transition != nil || props.Enter != nil || props.Exit != nil {
+		return
+	}
+
+	first, last := 0, len(list)
+	if first < last {
+		if animation, ok := positionedAnimation(list[first], map[string]interface{}{"time": "start", "transition": true}); ok {
+			props.Transition = animation
+			first++
+		}
+	}
+	if first < last {
+		if animation, ok := positionedAnimation(list[first], map[string]interface{}{"time": "start"}); ok {
+			props.Enter = animation
+			first++
+		}
+	}
+	if first < last {
+		if animation, ok := positionedAnimation(list[last-1], map[string]interface{}{"time": "end", "reversed": true}); ok {
+			props.Exit = animation
+			last--
+		}
+	}
+
+	props.Animations = nil
+	for _, item := range list[first:last] {
+		if data, ok := item.(map[string]interface{}); ok {
+			if animation, ok := animationFromMap(data); ok {
+				item = animation
+			}
+		}
+		props.Animations = append(props.Animations, item)
+	}
+}
+
+// positionedAnimation converts an animation whose position properties are exactly the given
+// ones, which ToMap adds back for a Transition, Enter or Exit.
+func positionedAnimation(item interface{}, position map[string]interface{}) (interface{}, bool) {
+	data, ok := item.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	rest := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		rest[key] = value
+	}
+	for _, key := range []string{"time", "reversed", "transition"} {
+		if !reflect.DeepEqual(data[key], position[key]) {
+			return nil, false
+		}
+		delete(rest, key)
+	}
+	return animationFromMap(rest)
+}
+
+// animationFromMap converts the JSON representation of an animation into an animation struct,
+// provided that its ToMap produces the same JSON again.
+func animationFromMap(data map[string]interface{}) (interface{}, bool) {
+	var candidates []func() interface{}
+	switch data["type"] {
+	case "text-slide":
+		candidates = []func() interface{}{
+			func() interface{} {
+				var slide TextSlide
+				decodeAnimation(data, &slide)
+				return &slide
+			},
+			func() interface{} {
+				var props animations.TextSlideProperties
+				decodeAnimation(data, &props)
+				return animations.NewTextSlide(props)
+			},
+		}
+	case "fade":
+		candidates = append(candidates, func() interface{} {
+			var props animations.FadeProperties
+			decodeAnimation(data, &props)
+			return animations.NewFade(props)
+		})
+	case "slide":
+		candidates = append(candidates, func() interface{} {
+			var props animations.SlideProperties
+			decodeAnimation(data, &props)
+			return animations.NewSlide(props)
+		})
+	case "scale":
+		candidates = append(candidates, func() interface{} {
+			var props animations.ScaleProperties
+			decodeAnimation(data, &props)
+			return animations.NewScale(props)
+		})
+	case "spin":
+		candidates = append(candidates, func() interface{} {
+			var props animations.SpinProperties
+			decodeAnimation(data, &props)
+			return animations.NewSpin(props)
+		})
+	case "text-appear":
+		candidates = append(candidates, func() interface{} {
+			var props animations.TextAppearProperties
+			decodeAnimation(data, &props)
+			return animations.NewTextAppear(props)
+		})
+	case "text-typewriter":
+		candidates = append(candidates, func() interface{} {
+			var props animations.TextTypewriterProperties
+			decodeAnimation(data, &props)
+			return animations.NewTextTypewriter(props)
+		})
+	}
+
+	for _, candidate := range candidates {
+		animation := candidate()
+		if sameJSON(animation.(interface{ ToMap() map[string]interface{} }).ToMap(), data) {
+			return animation, true
+		}
+	}
+	return nil, false
+}
+
+func decodeAnimation(data map[string]interface{}, props interface{}) {
+	dataJSON, _ := json.Marshal(data)
+	json.Unmarshal(dataJSON, props)
+}
+
+// sameJSON reports whether two values have the same JSON representation.
+func sameJSON(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	var aValue, bValue interface{}
+	json.Unmarshal(aJSON, &aValue)
+	json.Unmarshal(bJSON, &bValue)
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,295 @@
+package creatomate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Lakeshore-Labs/creatomate-go/properties"
+)
+
+// ValidationError describes a property of a source that the API would reject.
+type ValidationError struct {
+	// The JSON path of the property, e.g. "elements[2].elements[0].font_size".
+	Path   string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Reason)
+}
+
+// enumValues lists the allowed values of the enum properties, by their JSON name.
+var enumValues = map[string][]string{
+	"output_format":        enum(properties.OutputFormatJPG, properties.OutputFormatPNG, properties.OutputFormatGIF, properties.OutputFormatMP4),
+	"gif_quality":          enum(properties.GifQualityFast, properties.GifQualityBest),
+	"emoji_style":          enum(properties.EmojiStyleFacebook, properties.EmojiStyleGoogle, properties.EmojiStyleTwitter, properties.EmojiStyleApple),
+	"fill_mode":            enum(properties.FillModeSolid, properties.FillModeLinear, properties.FillModeRadial),
+	"fit":                  enum(properties.FitFill, properties.FitContain, properties.FitCover, properties.FitNone),
+	"text_transform":       enum(properties.TextTransformNone, properties.TextTransformUppercase, properties.TextTransformLowercase, properties.TextTransformCapitalize),
+	"stroke_cap":           enum(properties.StrokeCapButt, properties.StrokeCapRound, properties.StrokeCapSquare),
+	"stroke_join":          enum(properties.StrokeJoinMiter, properties.StrokeJoinRound, properties.StrokeJoinBevel),
+	"flow_direction":       enum(properties.FlowDirectionLeftToRight, properties.FlowDirectionRightToLeft),
+	"mask_mode":            enum(properties.MaskModeAlpha, properties.MaskModeLuminance, properties.MaskModeInverted),
+	"blur_mode":            enum(properties.BlurModeGaussian, properties.BlurModeMotion),
+	"transcript_effect":    enum(properties.TranscriptEffectHighlight, properties.TranscriptEffectKaraoke),
+	"transcript_placement": enum(properties.TranscriptPlacementCenter, properties.TranscriptPlacementBottom),
+	"transcript_split":     enum(properties.TranscriptSplitWord, properties.TranscriptSplitLine, properties.TranscriptSplitNone),
+	"warp_mode": enum(properties.WarpModeArc, properties.WarpModeArch, properties.WarpModeBulge, properties.WarpModeFlag,
+		properties.WarpModeWave, properties.WarpModeFish, properties.WarpModeRise, properties.WarpModeFisheye),
+	"blend_mode": enum(properties.BlendModeNormal, properties.BlendModeMultiply, properties.BlendModeScreen,
+		properties.BlendModeOverlay, properties.BlendModeDarken, properties.BlendModeLighten, properties.BlendModeColorDodge,
+		properties.BlendModeColorBurn, properties.BlendModeHardLight, properties.BlendModeSoftLight,
+		properties.BlendModeDifference, properties.BlendModeExclusion, properties.BlendModeHue,
+		properties.BlendModeSaturation, properties.BlendModeColor, properties.BlendModeLuminosity),
+}
+
+func enum[T ~string](values ...T) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = string(value)
+	}
+	return result
+}
+
+// gifOnlyProperties lists the source properties that only apply to GIF renders.
+var gifOnlyProperties = []string{"gif_quality", "gif_compression", "loop"}
+
+// mediaElementTypes lists the element types that require a source URL.
+var mediaElementTypes = map[string]bool{"video": true, "image": true, "audio": true}
+
+// Validate checks the source for problems that the API would reject with a 400 response:
+// out of range CRF and GIF compression values, GIF options for other output formats, media
+// elements without a source URL, enum properties set to unknown values, including in
+// keyframes, and keyframes out of chronological order. All problems are returned together
+// as *ValidationError values joined with errors.Join, each addressed by its JSON path.
+func (s *Source) Validate() error {
+	return validateSourceMap(s.ToMap(), nil)
+}
+
+// sourceMap returns the JSON representation of a source passed to StartRender.
+func sourceMap(source interface{}) (map[string]interface{}, error) {
+	var data []byte
+	switch s := source.(type) {
+	case *Source:
+		return s.ToMap(), nil
+	case Source:
+		return s.ToMap(), nil
+	case map[string]interface{}:
+		return s, nil
+	case json.RawMessage:
+		data = s
+	case []byte:
+		data = s
+	case string:
+		data = []byte(s)
+	default:
+		var err error
+		if data, err = json.Marshal(s); err != nil {
+			return nil, fmt.Errorf("invalid source: %w", err)
+		}
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+	return result, nil
+}
+
+// validateSourceMap validates a source in its JSON representation. Elements whose name or ID
+// is the target of a source modification don't need a source of their own.
+func validateSourceMap(source map[string]interface{}, modifications map[string]interface{}) error {
+	data, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	v := &sourceValidator{modified: make(map[string]bool, len(modifications))}
+	for key := range modifications {
+		// "Name" sets the default property of an element, which is the source of media elements
+		v.modified[key] = true
+		if element, ok := strings.CutSuffix(key, ".source"); ok {
+			v.modified[element] = true
+		}
+	}
+	v.validateSource(decoded)
+	return errors.Join(v.errs...)
+}
+
+type sourceValidator struct {
+	errs []error
+
+	// The names and IDs of the elements whose source is set by modifications.
+	modified map[string]bool
+}
+
+func (v *sourceValidator) addError(path, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *sourceValidator) validateSource(source map[string]interface{}) {
+	format, _ := source["output_format"].(string)
+	if format == "" {
+		v.addError("output_format", "is required")
+	}
+	v.validateRange(source, "crf", "", 17, 51)
+	v.validateRange(source, "gif_compression", "", 0, 200)
+	if format != "" && format != string(properties.OutputFormatGIF) {
+		for _, key := range gifOnlyProperties {
+			if _, ok := source[key]; ok {
+				v.addError(key, "only applies to gif output, not %s", format)
+			}
+		}
+	}
+	v.validateEnums(source, "")
+	v.validateKeyframes(source, "")
+	v.validateElements(source, "")
+}
+
+func (v *sourceValidator) validateElements(parent map[string]interface{}, path string) {
+	value, ok := parent["elements"]
+	if !ok {
+		return
+	}
+	path = joinPath(path, "elements")
+	list, ok := value.([]interface{})
+	if !ok {
+		v.addError(path, "must be an array")
+		return
+	}
+
+	for i, item := range list {
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+		element, ok := item.(map[string]interface{})
+		if !ok {
+			v.addError(elementPath, "must be an object")
+			continue
+		}
+
+		elementType, _ := element["type"].(string)
+		if elementType == "" {
+			v.addError(joinPath(elementPath, "type"), "is required")
+		}
+		if mediaElementTypes[elementType] && !v.isModified(element) {
+			if source, _ := element["source"].(string); strings.TrimSpace(source) == "" {
+				v.addError(joinPath(elementPath, "source"), "must be a URL for %s elements", elementType)
+			}
+		}
+		v.validateEnums(element, elementPath)
+		v.validateKeyframes(element, elementPath)
+		v.validateElements(element, elementPath)
+	}
+}
+
+// isModified reports whether modifications set the source of the element.
+func (v *sourceValidator) isModified(element map[string]interface{}) bool {
+	for _, key := range []string{"name", "id"} {
+		if value, _ := element[key].(string); value != "" && v.modified[value] {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRange checks that a numeric property, if present, lies within [min, max].
+func (v *sourceValidator) validateRange(parent map[string]interface{}, key, path string, min, max float64) {
+	value, ok := parent[key]
+	if !ok {
+		return
+	}
+	number, ok := value.(float64)
+	if !ok {
+		v.addError(joinPath(path, key), "must be a number")
+		return
+	}
+	if number < min || number > max {
+		v.addError(joinPath(path, key), "must be between %g and %g, got %g", min, max, number)
+	}
+}
+
+// validateEnums checks the enum properties of an object, in the order of their keys.
+func (v *sourceValidator) validateEnums(object map[string]interface{}, path string) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		if _, ok := enumValues[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := joinPath(path, key)
+		if keyframes, ok := object[key].([]interface{}); ok {
+			for i, item := range keyframes {
+				if keyframe, ok := item.(map[string]interface{}); ok {
+					v.validateEnum(key, keyframe["value"], fmt.Sprintf("%s[%d].value", keyPath, i))
+				}
+			}
+			continue
+		}
+		v.validateEnum(key, object[key], keyPath)
+	}
+}
+
+// validateKeyframes checks that the keyframes of the animated properties of an object are in
+// chronological order.
+func (v *sourceValidator) validateKeyframes(object map[string]interface{}, path string) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		list, ok := object[key].([]interface{})
+		if !ok || key == "elements" || key == "animations" {
+			continue
+		}
+		previous := math.Inf(-1)
+		for i, item := range list {
+			keyframe, ok := item.(map[string]interface{})
+			if !ok {
+				break
+			}
+			time, ok := keyframe["time"].(float64)
+			if _, hasValue := keyframe["value"]; !ok || !hasValue {
+				break
+			}
+			if time < previous {
+				v.addError(fmt.Sprintf("%s[%d].time", joinPath(path, key), i), "must not come before the previous keyframe at %gs, got %gs", previous, time)
+			}
+			previous = time
+		}
+	}
+}
+
+func (v *sourceValidator) validateEnum(key string, value interface{}, path string) {
+	allowed := enumValues[key]
+	if s, ok := value.(string); ok {
+		if s == "" && key == "output_format" {
+			// Reported as missing instead
+			return
+		}
+		for _, candidate := range allowed {
+			if s == candidate {
+				return
+			}
+		}
+	}
+	v.addError(path, "must be one of %s, got %v", strings.Join(allowed, ", "), value)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,265 @@
+package creatomate
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the spans and metrics recorded by the client. Durations are in seconds.
+const (
+	// A span around Client.Render, from starting the render until all renders have finished.
+	SpanRender = "creatomate.render"
+
+	// The wall time of Client.Render.
+	MetricRenderDuration = "creatomate.render.duration"
+
+	// The time from calling StartRender until a render first has a status other than planned.
+	MetricTimeToStart = "creatomate.render.time_to_start"
+
+	// The time a render spent in a status, with the attribute "status".
+	MetricStatusDuration = "creatomate.render.status_duration"
+
+	// The number of failures, with the attribute "error_type", e.g. "timeout" or "render_failed".
+	MetricFailures = "creatomate.render.failures"
+)
+
+// Attribute is a key/value pair attached to a span or metric.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Telemetry receives spans and metrics from the client. Implementations must be safe for
+// concurrent use. Adapters for tracing libraries can carry spans in the returned context.
+type Telemetry interface {
+	// StartSpan starts a span, which is ended by calling End on the returned Span.
+	StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+
+	// Count adds delta to a counter.
+	Count(name string, delta int64, attrs ...Attribute)
+
+	// Observe records a value in a histogram.
+	Observe(name string, value float64, attrs ...Attribute)
+}
+
+// Span is an operation started by Telemetry.StartSpan.
+type Span interface {
+	// SetAttributes adds attributes to the span.
+	SetAttributes(attrs ...Attribute)
+
+	// End ends the span. err is the error the operation failed with, if any.
+	End(err error)
+}
+
+// NoopTelemetry discards all spans and metrics. It is the default of every client.
+type NoopTelemetry struct{}
+
+func (NoopTelemetry) StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (NoopTelemetry) Count(name string, delta int64, attrs ...Attribute) {}
+
+func (NoopTelemetry) Observe(name string, value float64, attrs ...Attribute) {}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+
+func (noopSpan) End(err error) {}
+
+// WithTelemetry sends spans and metrics of the client to telemetry.
+func WithTelemetry(telemetry Telemetry) Option {
+	return func(c *Client) {
+		if telemetry != nil {
+			c.telemetry = telemetry
+		}
+	}
+}
+
+// ExpvarTelemetry publishes metrics through the expvar package, under a single map. Counters
+// are integers, histograms are objects with a count, sum, min and max. Metrics with
+// attributes are keyed like "creatomate.render.failures{error_type=timeout}". Spans are
+// recorded as the histogram "<name>.span.duration" and, if they fail, the counter
+// "<name>.span.errors".
+type ExpvarTelemetry struct {
+	vars *expvar.Map
+	mu   sync.Mutex
+}
+
+// NewExpvarTelemetry publishes the metrics as the expvar variable with the given name. Since
+// expvar variables can't be unpublished, a name that was already published as a map by an
+// earlier call is reused on purpose: telemetries with the same name share their metrics, which
+// add up, e.g. across several clients. Use distinct names to keep metrics apart. It panics if
+// the name is taken by a variable that isn't an *expvar.Map.
+func NewExpvarTelemetry(name string) *ExpvarTelemetry {
+	vars, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		vars = expvar.NewMap(name)
+	}
+	return &ExpvarTelemetry{vars: vars}
+}
+
+// Vars returns the published map.
+func (e *ExpvarTelemetry) Vars() *expvar.Map {
+	return e.vars
+}
+
+func (e *ExpvarTelemetry) StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, &expvarSpan{telemetry: e, name: name, attrs: attrs, start: time.Now()}
+}
+
+func (e *ExpvarTelemetry) Count(name string, delta int64, attrs ...Attribute) {
+	e.vars.Add(metricKey(name, attrs), delta)
+}
+
+func (e *ExpvarTelemetry) Observe(name string, value float64, attrs ...Attribute) {
+	key := metricKey(name, attrs)
+
+	e.mu.Lock()
+	h, ok := e.vars.Get(key).(*expvarHistogram)
+	if !ok {
+		h = &expvarHistogram{}
+		e.vars.Set(key, h)
+	}
+	e.mu.Unlock()
+
+	h.observe(value)
+}
+
+type expvarSpan struct {
+	telemetry *ExpvarTelemetry
+	name      string
+	attrs     []Attribute
+	start     time.Time
+}
+
+func (s *expvarSpan) SetAttributes(attrs ...Attribute) {}
+
+func (s *expvarSpan) End(err error) {
+	s.telemetry.Observe(s.name+".span.duration", time.Since(s.start).Seconds(), s.attrs...)
+	if err != nil {
+		s.telemetry.Count(s.name+".span.errors", 1, s.attrs...)
+	}
+}
+
+type expvarHistogram struct {
+	mu       sync.Mutex
+	count    int64
+	sum      float64
+	min, max float64
+}
+
+func (h *expvarHistogram) observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		h.min, h.max = value, value
+	}
+	h.count++
+	h.sum += value
+	h.min = math.Min(h.min, value)
+	h.max = math.Max(h.max, value)
+}
+
+func (h *expvarHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return fmt.Sprintf(`{"count": %d, "sum": %g, "min": %g, "max": %g}`, h.count, h.sum, h.min, h.max)
+}
+
+// metricKey returns the name with the attributes appended in a stable order.
+func metricKey(name string, attrs []Attribute) string {
+	if len(attrs) == 0 {
+		return name
+	}
+
+	pairs := make([]string, len(attrs))
+	for i, attr := range attrs {
+		pairs[i] = attr.Key + "=" + attr.Value
+	}
+	sort.Strings(pairs)
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// errorType returns the kind of err for the failure metric.
+func errorType(err error) string {
+	var (
+		badRequest          *BadRequestError
+		invalidApiKey       *InvalidApiKeyError
+		insufficientCredits *InsufficientCreditsError
+		rateLimitExceeded   *RateLimitExceededError
+		connectionError     *ConnectionError
+		timeoutError        *TimeoutError
+		creatomateError     *CreatomateError
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &timeoutError):
+		return "timeout"
+	case errors.As(err, &badRequest):
+		return "bad_request"
+	case errors.As(err, &invalidApiKey):
+		return "invalid_api_key"
+	case errors.As(err, &insufficientCredits):
+		return "insufficient_credits"
+	case errors.As(err, &rateLimitExceeded):
+		return "rate_limit_exceeded"
+	case errors.As(err, &connectionError):
+		return "connection"
+	case errors.As(err, &creatomateError):
+		return "api_error"
+	default:
+		return "other"
+	}
+}
+
+// renderTimings records how long renders spend in each status.
+type renderTimings struct {
+	telemetry Telemetry
+	startedAt time.Time
+	since     map[string]time.Time
+	started   map[string]bool
+}
+
+// newRenderTimings starts timing the renders, which were started at startedAt. A zero
+// startedAt means the start is unknown, e.g. when resuming a batch.
+func newRenderTimings(telemetry Telemetry, renders []Render, startedAt, now time.Time) *renderTimings {
+	t := &renderTimings{
+		telemetry: telemetry,
+		startedAt: startedAt,
+		since:     make(map[string]time.Time, len(renders)),
+		started:   make(map[string]bool, len(renders)),
+	}
+	for _, render := range renders {
+		t.since[render.ID] = now
+		t.observeStart(render, now)
+	}
+	return t
+}
+
+// observe records that the render left the previous status at the given time.
+func (t *renderTimings) observe(render Render, previous RenderStatus, at time.Time) {
+	if since, ok := t.since[render.ID]; ok && previous != "" {
+		t.telemetry.Observe(MetricStatusDuration, at.Sub(since).Seconds(), Attribute{Key: "status", Value: string(previous)})
+	}
+	t.since[render.ID] = at
+	t.observeStart(render, at)
+}
+
+func (t *renderTimings) observeStart(render Render, at time.Time) {
+	if t.startedAt.IsZero() || t.started[render.ID] || render.Status == "" || render.Status == RenderStatusPlanned {
+		return
+	}
+	t.started[render.ID] = true
+	t.telemetry.Observe(MetricTimeToStart, at.Sub(t.startedAt).Seconds())
+}
@@ -0,0 +1,85 @@
+package creatomate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+)
+
+type Template struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+
+	// The source of the template with its elements decoded into the types of the elements
+	// package. Only set by GetTemplate.
+	Source *Source `json:"-"`
+}
+
+func (t *Template) UnmarshalJSON(data []byte) error {
+	type templateFields Template
+	var raw struct {
+		templateFields
+		Source map[string]interface{} `json:"source"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = Template(raw.templateFields)
+	if raw.Source != nil {
+		source, err := sourceFromMap(raw.Source)
+		if err != nil {
+			return fmt.Errorf("template %s: %w", t.ID, err)
+		}
+		t.Source = source
+	}
+	return nil
+}
+
+// ElementNames returns the names of all named elements in the template, including those
+// nested in compositions. These are the names that can be used in modifications.
+func (t *Template) ElementNames() []string {
+	if t.Source == nil {
+		return nil
+	}
+
+	var names []string
+	elements.Walk(t.Source.Properties.Elements, func(element interface{}) {
+		if name := elements.Name(element); name != "" {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+type ListTemplatesOptions struct {
+	// Only return templates that have all of these tags.
+	Tags []string
+}
+
+// ListTemplates fetches the templates of the project. The returned templates don't include their source.
+func (c *Client) ListTemplates(ctx context.Context, options ListTemplatesOptions) ([]Template, error) {
+	path := "/templates"
+	if len(options.Tags) > 0 {
+		path += "?" + url.Values{"tags": {strings.Join(options.Tags, ",")}}.Encode()
+	}
+
+	var templates []Template
+	err := c.httpRequest(ctx, "GET", path, nil, &templates)
+	return templates, err
+}
+
+// GetTemplate fetches a template including its source.
+func (c *Client) GetTemplate(ctx context.Context, id string) (*Template, error) {
+	var template Template
+	err := c.httpRequest(ctx, "GET", fmt.Sprintf("/templates/%s", url.PathEscape(id)), nil, &template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
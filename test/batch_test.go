@@ -0,0 +1,183 @@
+package creatomate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/creatomatetest"
+)
+
+func TestRenderBatchResumesFromCheckpoint(t *testing.T) {
+	var mu sync.Mutex
+	var started []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			templateID := payload["template_id"].(string)
+			started = append(started, templateID)
+			if templateID == "broken" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"hint":"Template not found"}`))
+				return
+			}
+			json.NewEncoder(w).Encode([]creatomate.Render{{ID: "render-" + templateID, Status: creatomate.RenderStatusPlanned}})
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/renders/")
+		json.NewEncoder(w).Encode(creatomate.Render{ID: id, Status: creatomate.RenderStatusSucceeded})
+	}))
+	defer server.Close()
+
+	checkpoint := creatomate.NewMemoryCheckpoint()
+	checkpoint.Save(creatomate.BatchRecord{Key: "done", Done: true, Renders: []creatomate.Render{{ID: "render-done", Status: creatomate.RenderStatusSucceeded}}})
+	checkpoint.Save(creatomate.BatchRecord{Key: "running", Renders: []creatomate.Render{{ID: "render-running", Status: creatomate.RenderStatusRendering}}})
+
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)),
+	)
+	results, err := client.RenderBatch(context.Background(), []creatomate.RenderOptions{
+		{TemplateID: "done"},
+		{TemplateID: "running"},
+		{TemplateID: "new"},
+		{TemplateID: "broken"},
+	}, creatomate.BatchOptions{
+		Concurrency: 2,
+		Keys:        []string{"done", "running", "new", "broken"},
+		Checkpoint:  checkpoint,
+	})
+	if err != nil {
+		t.Fatalf("RenderBatch failed: %v", err)
+	}
+
+	for i, key := range []string{"done", "running", "new"} {
+		if !results[i].Succeeded() || results[i].Key != key {
+			t.Errorf("Expected %s to succeed, got %+v", key, results[i])
+		}
+	}
+	if results[3].Succeeded() || results[3].Err == nil {
+		t.Errorf("Expected broken to fail, got %+v", results[3])
+	}
+	if !results[0].Resumed || !results[1].Resumed || results[2].Resumed {
+		t.Error("Expected only the checkpointed items to be resumed")
+	}
+	if len(started) != 2 {
+		t.Errorf("Expected only new and broken to be started, got %v", started)
+	}
+
+	record, _ := checkpoint.Load("running")
+	if record == nil || !record.Done || record.Renders[0].Status != creatomate.RenderStatusSucceeded {
+		t.Errorf("Expected checkpoint to record the finished render, got %+v", record)
+	}
+}
+
+func TestRenderBatchRetriesFailedItems(t *testing.T) {
+	server := creatomatetest.NewServer(creatomatetest.WithProgression(
+		creatomate.RenderStatusPlanned,
+		creatomate.RenderStatusFailed,
+	))
+	defer server.Close()
+	client := server.NewClient(creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)))
+
+	checkpoint := creatomate.NewMemoryCheckpoint()
+	items := []creatomate.RenderOptions{{TemplateID: "template-1"}}
+	results, err := client.RenderBatch(context.Background(), items, creatomate.BatchOptions{Checkpoint: checkpoint})
+	if err != nil {
+		t.Fatalf("RenderBatch failed: %v", err)
+	}
+	if results[0].Succeeded() {
+		t.Fatalf("Expected the item to fail, got %+v", results[0])
+	}
+	if record, _ := checkpoint.Load("0"); record == nil || !record.Done || record.Error == "" {
+		t.Errorf("Expected the failure to be recorded, got %+v", record)
+	}
+
+	server.SetProgression(creatomate.RenderStatusPlanned, creatomate.RenderStatusSucceeded)
+	results, err = client.RenderBatch(context.Background(), items, creatomate.BatchOptions{Checkpoint: checkpoint})
+	if err != nil {
+		t.Fatalf("RenderBatch failed: %v", err)
+	}
+	if !results[0].Succeeded() || results[0].Resumed {
+		t.Errorf("Expected the failed item to be rendered again, got %+v", results[0])
+	}
+}
+
+func TestRenderBatchCancelsAbandonedItems(t *testing.T) {
+	server := creatomatetest.NewServer(creatomatetest.WithProgression(creatomate.RenderStatusRendering))
+	defer server.Close()
+	client := server.NewClient(creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)))
+
+	checkpoint := creatomate.NewMemoryCheckpoint()
+	results, err := client.RenderBatch(context.Background(), []creatomate.RenderOptions{
+		{TemplateID: "template-1", CancelOnAbandon: true},
+		{TemplateID: "template-2"},
+	}, creatomate.BatchOptions{Checkpoint: checkpoint, Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("RenderBatch failed: %v", err)
+	}
+
+	for _, result := range results {
+		var timeoutErr *creatomate.TimeoutError
+		if !errors.As(result.Err, &timeoutErr) {
+			t.Errorf("Expected a timeout for item %d, got %v", result.Index, result.Err)
+		}
+	}
+	canceled, _ := server.Render(results[0].Renders[0].ID)
+	if canceled.Status != creatomate.RenderStatusFailed {
+		t.Errorf("Expected the render of the first item to be canceled, got status %s", canceled.Status)
+	}
+	if record, _ := checkpoint.Load("0"); record == nil || !record.Done {
+		t.Errorf("Expected the canceled item to be started again by a later run, got %+v", record)
+	}
+	if left, _ := server.Render(results[1].Renders[0].ID); left.Status != creatomate.RenderStatusRendering {
+		t.Errorf("Expected the render of the second item to be left alone, got status %s", left.Status)
+	}
+}
+
+func TestRenderBatchLimitsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				highest := maxInFlight.Load()
+				if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			json.NewEncoder(w).Encode([]creatomate.Render{{ID: "render", Status: creatomate.RenderStatusSucceeded}})
+			return
+		}
+		json.NewEncoder(w).Encode(creatomate.Render{ID: "render", Status: creatomate.RenderStatusSucceeded})
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL))
+	items := make([]creatomate.RenderOptions, 8)
+	for i := range items {
+		items[i] = creatomate.RenderOptions{TemplateID: "template"}
+	}
+	if _, err := client.RenderBatch(context.Background(), items, creatomate.BatchOptions{Concurrency: 3}); err != nil {
+		t.Fatalf("RenderBatch failed: %v", err)
+	}
+	if highest := maxInFlight.Load(); highest > 3 {
+		t.Errorf("Expected at most 3 items in flight, got %d", highest)
+	} else if highest < 2 {
+		t.Errorf("Expected items to be rendered concurrently, got %d in flight", highest)
+	}
+}
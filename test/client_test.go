@@ -0,0 +1,397 @@
+package creatomate_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+)
+
+func TestClientOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/renders/abc" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Unexpected Authorization header %q", got)
+		}
+		if got := r.Header.Get("User-Agent"); got != "Creatomate-Go/"+creatomate.ClientVersion+" my-service/1.0" {
+			t.Errorf("Unexpected User-Agent header %q", got)
+		}
+		json.NewEncoder(w).Encode(creatomate.Render{ID: "abc", Status: creatomate.RenderStatusSucceeded})
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL+"/"),
+		creatomate.WithHTTPClient(server.Client()),
+		creatomate.WithUserAgent("my-service/1.0"),
+	)
+
+	render, err := client.FetchRender(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("FetchRender failed: %v", err)
+	}
+	if render.Status != creatomate.RenderStatusSucceeded {
+		t.Errorf("Expected status succeeded, got %s", render.Status)
+	}
+}
+
+func TestClientWithTransportDoesNotModifySharedClient(t *testing.T) {
+	shared := &http.Client{}
+	creatomate.NewClient("test-key",
+		creatomate.WithHTTPClient(shared),
+		creatomate.WithTransport(http.DefaultTransport),
+	)
+	if shared.Transport != nil {
+		t.Error("Expected shared http.Client to be left untouched")
+	}
+}
+
+func TestClientWithTransportBeforeHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(creatomate.Render{ID: "abc", Status: creatomate.RenderStatusSucceeded})
+	}))
+	defer server.Close()
+
+	used := false
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		used = true
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithTransport(transport),
+		creatomate.WithHTTPClient(&http.Client{Timeout: time.Minute}),
+	)
+	if _, err := client.FetchRender(context.Background(), "abc"); err != nil {
+		t.Fatalf("FetchRender failed: %v", err)
+	}
+	if !used {
+		t.Error("Expected the transport to be kept when WithHTTPClient comes after it")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestClientRetriesWithRetryAfter(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(creatomate.Render{ID: "abc", Status: creatomate.RenderStatusRendering})
+	}))
+	defer server.Close()
+
+	var retries []creatomate.RetryAttempt
+	policy := creatomate.DefaultRetryPolicy()
+	policy.OnRetry = func(attempt creatomate.RetryAttempt) {
+		retries = append(retries, attempt)
+	}
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL), creatomate.WithRetryPolicy(policy))
+
+	if _, err := client.FetchRender(context.Background(), "abc"); err != nil {
+		t.Fatalf("FetchRender failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if len(retries) != 2 || retries[0].Delay != 0 {
+		t.Errorf("Expected 2 retries without delay, got %+v", retries)
+	}
+}
+
+func TestClientDoesNotRetryStartRenderByDefault(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL), creatomate.WithRetryPolicy(creatomate.DefaultRetryPolicy()))
+
+	if _, err := client.StartRender(context.Background(), creatomate.RenderOptions{TemplateID: "tpl"}); err == nil {
+		t.Fatal("Expected StartRender to fail")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestClientRetriesRateLimitedStartRender(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode([]creatomate.Render{{ID: "abc", Status: creatomate.RenderStatusPlanned}})
+	}))
+	defer server.Close()
+
+	var retries []creatomate.RetryAttempt
+	policy := creatomate.DefaultRetryPolicy()
+	policy.OnRetry = func(attempt creatomate.RetryAttempt) {
+		retries = append(retries, attempt)
+	}
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL), creatomate.WithRetryPolicy(policy))
+
+	if _, err := client.StartRender(context.Background(), creatomate.RenderOptions{TemplateID: "tpl"}); err != nil {
+		t.Fatalf("StartRender failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if len(retries) != 1 || retries[0].Method != http.MethodPost || retries[0].Delay != 0 {
+		t.Errorf("Expected 1 retry of the POST honoring Retry-After, got %+v", retries)
+	}
+}
+
+func TestListRendersPagination(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		switch r.URL.Query().Get("page") {
+		case "1":
+			json.NewEncoder(w).Encode([]creatomate.Render{{ID: "a"}, {ID: "b"}})
+		default:
+			json.NewEncoder(w).Encode([]creatomate.Render{{ID: "c"}})
+		}
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL))
+	it := client.ListRenders(context.Background(), creatomate.ListRendersOptions{
+		Statuses:   []creatomate.RenderStatus{creatomate.RenderStatusFailed},
+		TemplateID: "tpl",
+		PageSize:   2,
+	})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Render().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("ListRenders failed: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != "a" || ids[2] != "c" {
+		t.Errorf("Unexpected renders %v", ids)
+	}
+	if len(queries) != 2 || queries[0] != "limit=2&page=1&status=failed&template_id=tpl" {
+		t.Errorf("Unexpected queries %v", queries)
+	}
+}
+
+func TestGetTemplateDecodesElements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/templates/tpl" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"id": "tpl",
+			"name": "Promo",
+			"tags": ["promo"],
+			"source": {
+				"output_format": "mp4",
+				"elements": [
+					{"type": "video", "name": "Background", "source": "https://example.com/video.mp4"},
+					{"type": "composition", "name": "Card", "elements": [
+						{"type": "text", "name": "Title", "text": "Hello"}
+					]}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL))
+	template, err := client.GetTemplate(context.Background(), "tpl")
+	if err != nil {
+		t.Fatalf("GetTemplate failed: %v", err)
+	}
+
+	if _, ok := template.Source.Properties.Elements[1].(*elements.Composition); !ok {
+		t.Errorf("Expected a composition, got %T", template.Source.Properties.Elements[1])
+	}
+	names := template.ElementNames()
+	if len(names) != 3 || names[0] != "Background" || names[1] != "Card" || names[2] != "Title" {
+		t.Errorf("Unexpected element names %v", names)
+	}
+}
+
+func TestRateLimiterFollowsQuotaHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "0.1")
+		json.NewEncoder(w).Encode(creatomate.Render{ID: "abc"})
+	}))
+	defer server.Close()
+
+	limiter := creatomate.NewRateLimiter(1000, 10)
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithRateLimiter(creatomate.RouteFetchRender, limiter),
+	)
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchRender(context.Background(), "abc"); err != nil {
+			t.Fatalf("FetchRender failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected the second request to wait for the quota to reset, took %s", elapsed)
+	}
+}
+
+func TestRateLimiterRecoversWithoutQuotaHeaders(t *testing.T) {
+	withHeaders := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if withHeaders {
+			w.Header().Set("X-RateLimit-Remaining", "5")
+			w.Header().Set("X-RateLimit-Reset", "10")
+		}
+		json.NewEncoder(w).Encode(creatomate.Render{ID: "abc"})
+	}))
+	defer server.Close()
+
+	limiter := creatomate.NewRateLimiter(1000, 10)
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithRateLimiter(creatomate.RouteFetchRender, limiter),
+	)
+
+	if _, err := client.FetchRender(context.Background(), "abc"); err != nil {
+		t.Fatalf("FetchRender failed: %v", err)
+	}
+	if rate := limiter.Rate(); rate != 0.5 {
+		t.Errorf("Expected the quota headers to lower the rate to 0.5, got %g", rate)
+	}
+
+	withHeaders = false
+	if _, err := client.FetchRender(context.Background(), "abc"); err != nil {
+		t.Fatalf("FetchRender failed: %v", err)
+	}
+	if rate := limiter.Rate(); rate != 1000 {
+		t.Errorf("Expected the configured rate once the headers are gone, got %g", rate)
+	}
+}
+
+func TestInterceptorsAndLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "abc" {
+			t.Errorf("Expected the interceptor to add X-Trace, got %q", r.Header.Get("X-Trace"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"render-1","status":"succeeded"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	var statuses []int
+	client := creatomate.NewClient("secret-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithInterceptor(creatomate.InterceptorFuncs{
+			BeforeRequestFunc: func(req *http.Request) error {
+				req.Header.Set("X-Trace", "abc")
+				return nil
+			},
+			AfterResponseFunc: func(req *http.Request, resp *http.Response, body []byte, latency time.Duration) {
+				statuses = append(statuses, resp.StatusCode)
+			},
+		}),
+		creatomate.WithInterceptor(creatomate.NewLogInterceptor(logger, creatomate.LogOptions{Bodies: true, Headers: true})),
+	)
+
+	if _, err := client.FetchRender(context.Background(), "render-1"); err != nil {
+		t.Fatalf("FetchRender failed: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0] != http.StatusOK {
+		t.Errorf("Expected one response with status 200, got %v", statuses)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log entry, got %q: %v", logs.String(), err)
+	}
+	if entry["method"] != "GET" || entry["path"] != "/renders/render-1" || entry["status"] != float64(200) {
+		t.Errorf("Unexpected log entry: %v", entry)
+	}
+	if ids, _ := entry["render_ids"].([]interface{}); len(ids) != 1 || ids[0] != "render-1" {
+		t.Errorf("Expected render_ids [render-1], got %v", entry["render_ids"])
+	}
+	if strings.Contains(logs.String(), "secret-key") {
+		t.Error("Expected the Authorization header to be redacted")
+	}
+}
+
+func TestInterceptorOnErrorForAbortedRequests(t *testing.T) {
+	hookErr := errors.New("missing trace")
+	var reported []error
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL("http://127.0.0.1:0"),
+		creatomate.WithRateLimiter(creatomate.RouteFetchRender, creatomate.NewRateLimiter(0.001, 1)),
+		creatomate.WithInterceptor(creatomate.InterceptorFuncs{
+			BeforeRequestFunc: func(req *http.Request) error {
+				return hookErr
+			},
+			OnErrorFunc: func(req *http.Request, err error, latency time.Duration) {
+				reported = append(reported, err)
+			},
+		}),
+	)
+
+	if _, err := client.FetchRender(context.Background(), "abc"); !errors.Is(err, hookErr) {
+		t.Fatalf("Expected the BeforeRequest error, got %v", err)
+	}
+
+	// The limiter has no tokens left, so the request is aborted while waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchRender(ctx, "abc"); err == nil {
+		t.Fatal("Expected the rate limiter wait to fail")
+	}
+
+	if len(reported) != 2 || !errors.Is(reported[0], hookErr) || !errors.Is(reported[1], context.DeadlineExceeded) {
+		t.Errorf("Expected OnError for the failed hook and the canceled wait, got %v", reported)
+	}
+}
+func TestInterceptorOnError(t *testing.T) {
+	var failed error
+	client := creatomate.NewClient("key",
+		creatomate.WithBaseURL("http://127.0.0.1:1"),
+		creatomate.WithRetryPolicy(creatomate.RetryPolicy{MaxAttempts: 1}),
+		creatomate.WithInterceptor(creatomate.InterceptorFuncs{
+			OnErrorFunc: func(req *http.Request, err error, latency time.Duration) {
+				failed = err
+			},
+		}),
+	)
+
+	_, err := client.FetchRender(context.Background(), "render-1")
+	var connectionError *creatomate.ConnectionError
+	if !errors.As(failed, &connectionError) || !errors.As(err, &connectionError) {
+		t.Errorf("Expected OnError and FetchRender to report a ConnectionError, got %v and %v", failed, err)
+	}
+}
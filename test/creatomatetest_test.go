@@ -0,0 +1,430 @@
+package creatomate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/creatomatetest"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+	"github.com/Lakeshore-Labs/creatomate-go/properties"
+	"github.com/Lakeshore-Labs/creatomate-go/webhook"
+)
+
+func TestFakeServerRenderWithSignedWebhook(t *testing.T) {
+	secret := []byte("webhook-secret")
+	succeeded := make(chan creatomate.Render, 1)
+	receiver := httptest.NewServer(&webhook.Handler{
+		Secret: secret,
+		OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+			succeeded <- render
+			return nil
+		},
+	})
+	defer receiver.Close()
+
+	server := creatomatetest.NewServer()
+	defer server.Close()
+
+	server.FailNext(creatomate.RouteFetchRender, http.StatusTooManyRequests, "Slow down")
+	policy := creatomate.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	client := server.NewClient(
+		creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)),
+		creatomate.WithRetryPolicy(policy),
+		creatomate.WithWebhookSecret(secret),
+	)
+
+	source := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		Elements: []interface{}{
+			elements.NewVideo(elements.VideoProperties{Source: "https://example.com/video.mp4"}),
+		},
+	})
+	renders, err := client.Render(context.Background(), creatomate.RenderOptions{
+		Source:     source,
+		WebhookURL: receiver.URL,
+		Metadata:   "order-42",
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(renders) != 1 || renders[0].Status != creatomate.RenderStatusSucceeded {
+		t.Fatalf("Expected one succeeded render, got %+v", renders)
+	}
+
+	select {
+	case render := <-succeeded:
+		if render.ID != renders[0].ID || render.Metadata != "order-42" {
+			t.Errorf("Unexpected webhook render %+v", render)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the webhook to be delivered")
+	}
+
+	sources := server.Sources()
+	if len(sources) != 1 {
+		t.Fatalf("Expected one recorded source, got %d", len(sources))
+	}
+	var recorded map[string]interface{}
+	json.Unmarshal(sources[0], &recorded)
+	if recorded["output_format"] != "mp4" {
+		t.Errorf("Unexpected recorded source %s", sources[0])
+	}
+}
+
+func TestFakeServerInjectedErrors(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+
+	server.FailNext(creatomate.RouteStartRender, http.StatusPaymentRequired, "")
+	client := server.NewClient()
+
+	_, err := client.StartRender(context.Background(), creatomate.RenderOptions{TemplateID: "tpl"})
+	var creditsError *creatomate.InsufficientCreditsError
+	if !errors.As(err, &creditsError) {
+		t.Fatalf("Expected InsufficientCreditsError, got %v", err)
+	}
+
+	if _, err := client.StartRender(context.Background(), creatomate.RenderOptions{TemplateID: "tpl"}); err != nil {
+		t.Fatalf("Expected the failure to be used up, got %v", err)
+	}
+}
+
+func TestMemoryRendererOutcomes(t *testing.T) {
+	renderer := creatomatetest.NewMemoryRenderer()
+	renderer.SetOutcomeFunc(func(options creatomate.RenderOptions) creatomatetest.Outcome {
+		if options.TemplateID == "broken" {
+			return creatomatetest.Outcome{Status: creatomate.RenderStatusFailed, ErrorMessage: "Missing font"}
+		}
+		return creatomatetest.Outcome{Renders: 2}
+	})
+
+	var r creatomate.Renderer = renderer
+	renders, err := r.Render(context.Background(), creatomate.RenderOptions{TemplateID: "promo"}, time.Minute)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(renders) != 2 || renders[1].Status != creatomate.RenderStatusSucceeded || renders[1].URL == "" {
+		t.Errorf("Expected two succeeded renders, got %+v", renders)
+	}
+
+	renders, err = r.Render(context.Background(), creatomate.RenderOptions{TemplateID: "broken"}, time.Minute)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if renders[0].Status != creatomate.RenderStatusFailed || renders[0].ErrorMessage != "Missing font" {
+		t.Errorf("Expected a failed render, got %+v", renders[0])
+	}
+	if len(renderer.Requests()) != 2 {
+		t.Errorf("Expected 2 recorded requests, got %d", len(renderer.Requests()))
+	}
+}
+
+func TestMemoryRendererReentrantOutcomeAndTimeout(t *testing.T) {
+	renderer := creatomatetest.NewMemoryRenderer()
+	renderer.SetOutcomeFunc(func(options creatomate.RenderOptions) creatomatetest.Outcome {
+		// Looking at the renderer from the outcome function must not deadlock
+		if len(renderer.Requests()) > 1 {
+			return creatomatetest.Outcome{Status: creatomate.RenderStatusRendering}
+		}
+		return creatomatetest.Outcome{}
+	})
+
+	if _, err := renderer.Render(context.Background(), creatomate.RenderOptions{TemplateID: "promo"}, time.Minute); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	start := time.Now()
+	renders, err := renderer.Render(context.Background(), creatomate.RenderOptions{TemplateID: "promo"}, 50*time.Millisecond)
+	var timeoutError *creatomate.TimeoutError
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("Expected a TimeoutError for the render that is still in progress, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected Render to wait for the timeout, returned after %s", elapsed)
+	}
+	if len(renders) != 0 || len(timeoutError.Renders) != 1 || timeoutError.Renders[0].Status != creatomate.RenderStatusRendering {
+		t.Errorf("Expected one unfinished render, got %+v and %+v", renders, timeoutError.Renders)
+	}
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	source := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		Elements: []interface{}{
+			elements.NewVideo(elements.VideoProperties{Source: "https://example.com/video.mp4"}),
+		},
+	})
+	options := creatomate.RenderOptions{Source: source, Metadata: "order-42"}
+
+	recorder, err := creatomatetest.NewCassette(path, creatomatetest.ModeAuto, nil)
+	if err != nil {
+		t.Fatalf("NewCassette failed: %v", err)
+	}
+	if !recorder.Recording() {
+		t.Fatal("Expected a missing cassette to be recorded")
+	}
+	client := creatomate.NewClient("secret-key", creatomate.WithBaseURL(server.URL), creatomate.WithTransport(recorder))
+	recorded, err := client.StartRender(context.Background(), options)
+	if err != nil {
+		t.Fatalf("StartRender failed: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read cassette: %v", err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("Expected the API key to be redacted from the cassette")
+	}
+
+	// Replay without a server behind it
+	server.Close()
+	player, err := creatomatetest.NewCassette(path, creatomatetest.ModeAuto, nil)
+	if err != nil {
+		t.Fatalf("NewCassette failed: %v", err)
+	}
+	if player.Recording() {
+		t.Fatal("Expected an existing cassette to be replayed")
+	}
+	client = creatomate.NewClient("other-key", creatomate.WithBaseURL(server.URL), creatomate.WithTransport(player))
+	replayed, err := client.StartRender(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Replayed StartRender failed: %v", err)
+	}
+	if len(replayed) != 1 || replayed[0].ID != recorded[0].ID {
+		t.Errorf("Expected the recorded render %s, got %+v", recorded[0].ID, replayed)
+	}
+
+	// Every interaction is replayed once, and a different body doesn't match
+	options.Metadata = "order-43"
+	_, err = client.StartRender(context.Background(), options)
+	var mismatch *creatomatetest.CassetteMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a CassetteMismatchError, got %v", err)
+	}
+	if mismatch.Request.Method != http.MethodPost || mismatch.Request.Path != "/renders" {
+		t.Errorf("Unexpected mismatched request: %+v", mismatch.Request)
+	}
+}
+
+func TestCassetteMatchesSignedMetadata(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	options := creatomate.RenderOptions{TemplateID: "template", Metadata: "order-42", WebhookURL: "https://example.com/webhook"}
+	secret := creatomate.WithWebhookSecret([]byte("webhook-secret"))
+
+	recorder, err := creatomatetest.NewCassette(path, creatomatetest.ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("NewCassette failed: %v", err)
+	}
+	client := creatomate.NewClient("key", creatomate.WithBaseURL(server.URL), creatomate.WithTransport(recorder), secret)
+	if _, err := client.StartRender(context.Background(), options); err != nil {
+		t.Fatalf("StartRender failed: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A new token with another nonce still matches
+	player, err := creatomatetest.NewCassette(path, creatomatetest.ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewCassette failed: %v", err)
+	}
+	client = creatomate.NewClient("key", creatomate.WithBaseURL(server.URL), creatomate.WithTransport(player), secret)
+	if _, err := client.StartRender(context.Background(), options); err != nil {
+		t.Errorf("Expected the signed request to be replayed, got %v", err)
+	}
+}
+
+func TestRenderCancelsAbandonedRenders(t *testing.T) {
+	server := creatomatetest.NewServer(creatomatetest.WithProgression(
+		creatomate.RenderStatusPlanned,
+		creatomate.RenderStatusRendering,
+		creatomate.RenderStatusRendering,
+		creatomate.RenderStatusRendering,
+		creatomate.RenderStatusSucceeded,
+	))
+	defer server.Close()
+
+	client := server.NewClient(creatomate.WithPollStrategy(creatomate.FixedInterval(time.Hour)))
+
+	_, err := client.Render(context.Background(), creatomate.RenderOptions{
+		TemplateID:      "template-1",
+		CancelOnAbandon: true,
+	}, 20*time.Millisecond)
+	var timeoutError *creatomate.TimeoutError
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("Expected a TimeoutError, got %v", err)
+	}
+
+	render, ok := server.Render("render-1")
+	if !ok || render.Status != creatomate.RenderStatusFailed {
+		t.Errorf("Expected the abandoned render to be canceled, got %+v", render)
+	}
+
+	// Without the option, renders keep going
+	_, err = client.Render(context.Background(), creatomate.RenderOptions{TemplateID: "template-1"}, 20*time.Millisecond)
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("Expected a TimeoutError, got %v", err)
+	}
+	if render, _ := server.Render("render-2"); render.Status != creatomate.RenderStatusPlanned {
+		t.Errorf("Expected the render to be left alone, got status %s", render.Status)
+	}
+}
+
+func TestCancelAndDeleteRender(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	renders, err := client.StartRender(context.Background(), creatomate.RenderOptions{TemplateID: "template-1"})
+	if err != nil {
+		t.Fatalf("StartRender failed: %v", err)
+	}
+
+	canceled, err := client.CancelRender(context.Background(), renders[0].ID)
+	if err != nil {
+		t.Fatalf("CancelRender failed: %v", err)
+	}
+	if canceled.Status != creatomate.RenderStatusFailed {
+		t.Errorf("Expected the canceled render to fail, got status %s", canceled.Status)
+	}
+
+	var badRequest *creatomate.BadRequestError
+	if _, err := client.CancelRender(context.Background(), renders[0].ID); !errors.As(err, &badRequest) {
+		t.Errorf("Expected canceling a finished render to fail with a BadRequestError, got %v", err)
+	}
+
+	if err := client.DeleteRender(context.Background(), renders[0].ID); err != nil {
+		t.Fatalf("DeleteRender failed: %v", err)
+	}
+	if _, ok := server.Render(renders[0].ID); ok {
+		t.Error("Expected the render to be deleted")
+	}
+}
+
+func TestFakeServerListRenders(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	for _, options := range []creatomate.RenderOptions{
+		{TemplateID: "template-1"},
+		{TemplateID: "template-2"},
+		{TemplateID: "template-1"},
+		{TemplateID: "template-1"},
+	} {
+		if _, err := client.StartRender(context.Background(), options); err != nil {
+			t.Fatalf("StartRender failed: %v", err)
+		}
+	}
+	server.Advance("render-3")
+
+	list := func(options creatomate.ListRendersOptions) []string {
+		var ids []string
+		it := client.ListRenders(context.Background(), options)
+		for it.Next() {
+			ids = append(ids, it.Render().ID)
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("ListRenders failed: %v", err)
+		}
+		return ids
+	}
+
+	if ids := list(creatomate.ListRendersOptions{PageSize: 2}); strings.Join(ids, ",") != "render-1,render-2,render-3,render-4" {
+		t.Errorf("Expected every render once, got %v", ids)
+	}
+	if ids := list(creatomate.ListRendersOptions{TemplateID: "template-1", PageSize: 2}); strings.Join(ids, ",") != "render-1,render-3,render-4" {
+		t.Errorf("Expected the renders of the template, got %v", ids)
+	}
+	if ids := list(creatomate.ListRendersOptions{Statuses: []creatomate.RenderStatus{creatomate.RenderStatusRendering}}); strings.Join(ids, ",") != "render-3" {
+		t.Errorf("Expected the rendering render, got %v", ids)
+	}
+	if ids := list(creatomate.ListRendersOptions{CreatedAfter: time.Now().Add(time.Hour)}); len(ids) != 0 {
+		t.Errorf("Expected no renders created in the future, got %v", ids)
+	}
+}
+
+func TestFakeServerCancelDeliversWebhook(t *testing.T) {
+	deliveries := make(chan creatomate.Render, 1)
+	receiver := httptest.NewServer(&webhook.Handler{
+		OnFailed: func(ctx context.Context, render creatomate.Render) error {
+			deliveries <- render
+			return nil
+		},
+	})
+	defer receiver.Close()
+
+	server := creatomatetest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	renders, err := client.StartRender(context.Background(), creatomate.RenderOptions{TemplateID: "template-1", WebhookURL: receiver.URL})
+	if err != nil {
+		t.Fatalf("StartRender failed: %v", err)
+	}
+	if _, err := client.CancelRender(context.Background(), renders[0].ID); err != nil {
+		t.Fatalf("CancelRender failed: %v", err)
+	}
+
+	select {
+	case render := <-deliveries:
+		if render.ID != renders[0].ID || render.Status != creatomate.RenderStatusFailed {
+			t.Errorf("Unexpected delivery %+v", render)
+		}
+	default:
+		t.Fatal("Expected the canceled render to be delivered")
+	}
+	if delivered := server.Deliveries(); len(delivered) != 1 || delivered[0].StatusCode != http.StatusNoContent {
+		t.Errorf("Unexpected deliveries %+v", delivered)
+	}
+}
+
+func TestCassetteLeavesRequestUnmodified(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+
+	recorder, err := creatomatetest.NewCassette(filepath.Join(t.TempDir(), "cassette.json"), creatomatetest.ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("NewCassette failed: %v", err)
+	}
+
+	body := io.NopCloser(strings.NewReader(`{"template_id":"template"}`))
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/renders", body)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	resp, err := recorder.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if req.Body != body {
+		t.Error("Expected the cassette not to replace the body of the caller's request")
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected the request to be sent with its body, got status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,159 @@
+package creatomate_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+)
+
+func TestDownloadToFileResumesInterruptedTransfer(t *testing.T) {
+	content := bytes.Repeat([]byte("creatomate"), 10000)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "" {
+			t.Error("Expected the API key not to be sent to the file host")
+		}
+		if requests == 1 {
+			// Send half of the file, then drop the connection
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.Write(content[:len(content)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "output.mp4", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	policy := creatomate.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	client := creatomate.NewClient("test-key", creatomate.WithRetryPolicy(policy))
+
+	render := creatomate.Render{
+		ID:       "abc",
+		Status:   creatomate.RenderStatusSucceeded,
+		URL:      server.URL + "/output.mp4",
+		FileSize: int64(len(content)),
+	}
+
+	var lastWritten, lastTotal int64
+	path := filepath.Join(t.TempDir(), "output.mp4")
+	err := client.DownloadToFile(context.Background(), render, path, creatomate.DownloadProgress(func(written, total int64) {
+		lastWritten, lastTotal = written, total
+	}))
+	if err != nil {
+		t.Fatalf("DownloadToFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Errorf("Downloaded %d bytes that don't match the original %d bytes", len(data), len(content))
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if lastWritten != int64(len(content)) || lastTotal != int64(len(content)) {
+		t.Errorf("Unexpected final progress %d/%d", lastWritten, lastTotal)
+	}
+}
+
+func TestDownloadOutlastsClientTimeout(t *testing.T) {
+	content := bytes.Repeat([]byte("creatomate"), 1000)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := requests.Add(1)
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		chunk := len(content) / 10
+		for i := 0; i < len(content); i += chunk {
+			if attempt == 1 && i == len(content)/2 {
+				// Stall longer than the timeout once
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.Write(content[i : i+chunk])
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	policy := creatomate.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	client := creatomate.NewClient("test-key",
+		creatomate.WithHTTPClient(&http.Client{Timeout: 100 * time.Millisecond}),
+		creatomate.WithRetryPolicy(policy),
+	)
+
+	render := creatomate.Render{
+		ID:       "abc",
+		Status:   creatomate.RenderStatusSucceeded,
+		URL:      server.URL + "/output.mp4",
+		FileSize: int64(len(content)),
+	}
+
+	var buf bytes.Buffer
+	if err := client.Download(context.Background(), render, &buf); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), content) {
+		t.Errorf("Downloaded %d bytes that don't match the original %d bytes", buf.Len(), len(content))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected the stalled transfer to be retried once, got %d requests", n)
+	}
+}
+
+func TestDownloadToFileDiscardsStalePart(t *testing.T) {
+	content := bytes.Repeat([]byte("creatomate"), 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "output.mp4", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key")
+	render := creatomate.Render{
+		ID:       "abc",
+		Status:   creatomate.RenderStatusSucceeded,
+		URL:      server.URL + "/output.mp4",
+		FileSize: int64(len(content)),
+	}
+
+	cases := []struct {
+		name string
+		part []byte
+		url  string
+	}{
+		{"part of another URL", []byte("stale"), server.URL + "/other.mp4"},
+		{"part without URL", []byte("stale"), ""},
+		{"part larger than the file", bytes.Repeat([]byte("x"), len(content)+10), render.URL},
+	}
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), "output.mp4")
+		os.WriteFile(path+".part", c.part, 0o644)
+		if c.url != "" {
+			os.WriteFile(path+".part.url", []byte(c.url), 0o644)
+		}
+
+		if err := client.DownloadToFile(context.Background(), render, path); err != nil {
+			t.Errorf("%s: DownloadToFile failed: %v", c.name, err)
+			continue
+		}
+		if data, _ := os.ReadFile(path); !bytes.Equal(data, content) {
+			t.Errorf("%s: downloaded %d bytes that don't match the original %d bytes", c.name, len(data), len(content))
+		}
+		if _, err := os.Stat(path + ".part.url"); !os.IsNotExist(err) {
+			t.Errorf("%s: expected the URL file to be removed, got %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package creatomate_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+)
+
+func TestAPIErrorDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"hint":"Invalid source"}`))
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL))
+	_, err := client.FetchRender(context.Background(), "abc")
+
+	var badRequest *creatomate.BadRequestError
+	if !errors.As(err, &badRequest) {
+		t.Fatalf("Expected BadRequestError, got %T", err)
+	}
+
+	var apiError *creatomate.CreatomateError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("Expected errors.As to find a CreatomateError in %T", err)
+	}
+	if apiError.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", apiError.StatusCode)
+	}
+	if apiError.RequestID != "req-123" {
+		t.Errorf("Expected request ID req-123, got %q", apiError.RequestID)
+	}
+	if string(apiError.Body) != `{"hint":"Invalid source"}` {
+		t.Errorf("Unexpected body %q", apiError.Body)
+	}
+	if creatomate.IsRetryable(err) {
+		t.Error("Expected bad request not to be retryable")
+	}
+}
+
+func TestConnectionErrorWrapsCause(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := creatomate.NewClient("test-key", creatomate.WithBaseURL(server.URL))
+	_, err := client.FetchRender(context.Background(), "abc")
+
+	var connectionError *creatomate.ConnectionError
+	if !errors.As(err, &connectionError) {
+		t.Fatalf("Expected ConnectionError, got %T", err)
+	}
+	if connectionError.Unwrap() == nil {
+		t.Error("Expected the underlying network error to be kept")
+	}
+	if !creatomate.IsRetryable(err) || !creatomate.IsTemporary(err) {
+		t.Error("Expected connection error to be retryable and temporary")
+	}
+}
@@ -75,12 +75,12 @@ func TestTextOverlayJSON(t *testing.T) {
 			}),
 			elements.NewText(elements.TextProperties{
 				ElementProperties: elements.ElementProperties{
-					Y:          "75%",
-					Width:      "100%",
-					Height:     "50%",
-					XPadding:   "5 vw",
-					YPadding:   "5 vh",
-					YAlignment: "100%",
+					Y:          creatomate.Static[any]("75%"),
+					Width:      creatomate.Static[any]("100%"),
+					Height:     creatomate.Static[any]("50%"),
+					XPadding:   creatomate.Static[any]("5 vw"),
+					YPadding:   creatomate.Static[any]("5 vh"),
+					YAlignment: creatomate.Static[any]("100%"),
 					Enter: creatomate.NewTextSlide(map[string]interface{}{
 						"duration":         2,
 						"easing":           "quadratic-out",
@@ -92,7 +92,7 @@ func TestTextOverlayJSON(t *testing.T) {
 				Text:       "This text adjusts automatically to the size of the video. 🔥",
 				Font:       font,
 				Background: creatomate.NewTextBackground("rgba(255,255,255,0.69)", "23%", "8%", "0%", "0%"),
-				FillColor:  "#333333",
+				FillColor:  creatomate.Static[any]("#333333"),
 			}),
 		},
 	})
@@ -192,18 +192,18 @@ func TestCompositionsJSON(t *testing.T) {
 		Elements: []interface{}{
 			elements.NewComposition(elements.CompositionProperties{
 				ElementProperties: elements.ElementProperties{
-					Width: []interface{}{
-						creatomate.NewKeyframe("100%", 1),
-						creatomate.NewKeyframe("50%", 3),
-					},
-					Height: []interface{}{
-						creatomate.NewKeyframe("100%", 3),
-						creatomate.NewKeyframe("50%", 4),
-					},
-					YRotation: []interface{}{
-						creatomate.NewKeyframe(0, 4),
-						creatomate.NewKeyframe(360, 5),
-					},
+					Width: creatomate.Animated(
+						creatomate.NewKeyframe[any]("100%", 1),
+						creatomate.NewKeyframe[any]("50%", 3),
+					),
+					Height: creatomate.Animated(
+						creatomate.NewKeyframe[any]("100%", 3),
+						creatomate.NewKeyframe[any]("50%", 4),
+					),
+					YRotation: creatomate.Animated(
+						creatomate.NewKeyframe[any](0, 4),
+						creatomate.NewKeyframe[any](360, 5),
+					),
 				},
 				Elements: []interface{}{
 					elements.NewImage(elements.ImageProperties{
@@ -212,9 +212,9 @@ func TestCompositionsJSON(t *testing.T) {
 					}),
 					elements.NewText(elements.TextProperties{
 						ElementProperties: elements.ElementProperties{
-							Width:      "100%",
-							Height:     "10%",
-							XAlignment: "50%",
+							Width:      creatomate.Static[any]("100%"),
+							Height:     creatomate.Static[any]("10%"),
+							XAlignment: creatomate.Static[any]("50%"),
 						},
 						Text:       "Place elements in the same composition to group them",
 						Background: creatomate.NewTextBackground("#fff", "25%", "25%", "20%", "0%"),
@@ -262,45 +262,45 @@ func TestKeyframesJSON(t *testing.T) {
 		Elements: []interface{}{
 			elements.NewShape(elements.ShapeProperties{
 				ElementProperties: elements.ElementProperties{
-					Width:  "23.5227%",
-					Height: "41.8179%",
-					XScale: []interface{}{
-						creatomate.NewKeyframe("20%", 0),
-						creatomate.NewKeyframeWithEasing("100%", 2, "elastic-out"),
-					},
-					YScale: []interface{}{
-						creatomate.NewKeyframe("20%", 0),
-						creatomate.NewKeyframeWithEasing("100%", 2, "elastic-out"),
-					},
-					ZRotation: []interface{}{
-						creatomate.NewKeyframe(-90, 0),
-						creatomate.NewKeyframeWithEasing(0, 2, "elastic-out"),
-					},
+					Width:  creatomate.Static[any]("23.5227%"),
+					Height: creatomate.Static[any]("41.8179%"),
+					XScale: creatomate.Animated(
+						creatomate.NewKeyframe[any]("20%", 0),
+						creatomate.NewKeyframeWithEasing[any]("100%", 2, "elastic-out"),
+					),
+					YScale: creatomate.Animated(
+						creatomate.NewKeyframe[any]("20%", 0),
+						creatomate.NewKeyframeWithEasing[any]("100%", 2, "elastic-out"),
+					),
+					ZRotation: creatomate.Animated(
+						creatomate.NewKeyframe[any](-90, 0),
+						creatomate.NewKeyframeWithEasing[any](0, 2, "elastic-out"),
+					),
 				},
-				FillColor: []interface{}{
-					creatomate.NewKeyframe("#333333", 0),
-					creatomate.NewKeyframe("#0079ff", 0.94),
-					creatomate.NewKeyframe("#0079ff", 2),
-					creatomate.NewKeyframe("rgba(0,121,255,0)", 2.5),
-				},
-				StrokeColor: "rgba(0,121,255,1)",
-				StrokeWidth: []interface{}{
-					creatomate.NewKeyframe("0 vmin", 2),
-					creatomate.NewKeyframe("4.3 vmin", 2.5),
-					creatomate.NewKeyframe("0 vmin", 3.5),
-				},
-				StrokeStart: []interface{}{
-					creatomate.NewKeyframe("0%", 2.5),
-					creatomate.NewKeyframe("100%", 3.5),
-				},
-				StrokeOffset: []interface{}{
-					creatomate.NewKeyframe("0%", 2.5),
-					creatomate.NewKeyframe("50%", 3.5),
-				},
-				Path: []interface{}{
+				FillColor: creatomate.Animated(
+					creatomate.NewKeyframe[any]("#333333", 0),
+					creatomate.NewKeyframe[any]("#0079ff", 0.94),
+					creatomate.NewKeyframe[any]("#0079ff", 2),
+					creatomate.NewKeyframe[any]("rgba(0,121,255,0)", 2.5),
+				),
+				StrokeColor: creatomate.Static("rgba(0,121,255,1)"),
+				StrokeWidth: creatomate.Animated(
+					creatomate.NewKeyframe[any]("0 vmin", 2),
+					creatomate.NewKeyframe[any]("4.3 vmin", 2.5),
+					creatomate.NewKeyframe[any]("0 vmin", 3.5),
+				),
+				StrokeStart: creatomate.Animated(
+					creatomate.NewKeyframe[any]("0%", 2.5),
+					creatomate.NewKeyframe[any]("100%", 3.5),
+				),
+				StrokeOffset: creatomate.Animated(
+					creatomate.NewKeyframe[any]("0%", 2.5),
+					creatomate.NewKeyframe[any]("50%", 3.5),
+				),
+				Path: creatomate.Animated(
 					creatomate.NewKeyframe("M 0 0 L 100 0 L 100 100 L 0 100 L 0 0 Z", 0.94),
 					creatomate.NewKeyframeWithEasing("M -20 -20 C 15 -55 85 -55 120 -20 C 155 15 155 85 120 120 C 85 155 15 155 -20 120 C -55 85 -55 15 -20 -20 Z", 2.5, "elastic-out"),
-				},
+				),
 			}),
 		},
 	})
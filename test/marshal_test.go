@@ -0,0 +1,274 @@
+package creatomate_test
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/animations"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+	"github.com/Lakeshore-Labs/creatomate-go/properties"
+)
+
+func TestSourceMarshalJSONMatchesToMap(t *testing.T) {
+	for _, name := range []string{"compositions", "keyframes", "simple-video", "text-overlay"} {
+		data, err := os.ReadFile("testdata/json-outputs/" + name + ".json")
+		if err != nil {
+			t.Fatalf("Failed to read fixture: %v", err)
+		}
+
+		var source creatomate.Source
+		if err := json.Unmarshal(data, &source); err != nil {
+			t.Fatalf("%s: Unmarshal failed: %v", name, err)
+		}
+		marshaled, err := json.Marshal(source)
+		if err != nil {
+			t.Fatalf("%s: Marshal failed: %v", name, err)
+		}
+
+		var expected, actual interface{}
+		json.Unmarshal(data, &expected)
+		json.Unmarshal(marshaled, &actual)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s: JSON doesn't match.\nExpected:\n%s\n\nGot:\n%s", name, data, marshaled)
+		}
+	}
+}
+
+func TestSourceEmbeddedInStruct(t *testing.T) {
+	type job struct {
+		Name   string             `json:"name"`
+		Source *creatomate.Source `json:"source"`
+	}
+
+	original := job{
+		Name: "intro",
+		Source: creatomate.NewSource(creatomate.SourceProperties{
+			OutputFormat: properties.OutputFormatMP4,
+			Elements: []interface{}{
+				elements.NewComposition(elements.CompositionProperties{
+					Elements: []interface{}{
+						elements.NewText(elements.TextProperties{
+							Text: "Hello",
+							Font: creatomate.NewFont("Open Sans", 700),
+						}),
+					},
+				}),
+			},
+		}),
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded job
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	composition, ok := decoded.Source.Properties.Elements[0].(*elements.Composition)
+	if !ok {
+		t.Fatalf("Expected a composition, got %T", decoded.Source.Properties.Elements[0])
+	}
+	text, ok := elements.Children(composition)[0].(*elements.Text)
+	if !ok {
+		t.Fatalf("Expected a text element, got %T", elements.Children(composition)[0])
+	}
+	props := text.Properties.(elements.TextProperties)
+	if font, ok := props.Font.(*creatomate.Font); !ok || font.Family != "Open Sans" {
+		t.Errorf("Expected the font to survive the round trip, got %#v", props.Font)
+	}
+
+	again, _ := json.Marshal(decoded)
+	if string(again) != string(data) {
+		t.Errorf("Expected the same JSON after a round trip.\nFirst:  %s\nSecond: %s", data, again)
+	}
+}
+
+func TestElementMarshalJSON(t *testing.T) {
+	text := elements.NewText(elements.TextProperties{
+		ElementProperties: elements.ElementProperties{
+			Enter: animations.NewFade(animations.FadeProperties{
+				AnimationProperties: animations.AnimationProperties{Duration: 1},
+			}),
+		},
+		Text:       "Hello",
+		Background: creatomate.NewTextBackground("#fff", "10%", nil, nil, nil),
+		FontFamily: "Roboto",
+	})
+
+	data, err := json.Marshal(text)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	expected := `{"animations":[{"duration":1,"time":"start","type":"fade"}],"background_color":"#fff","background_x_padding":"10%","font_family":"Roboto","text":"Hello","type":"text"}`
+	if string(data) != expected {
+		t.Errorf("Unexpected JSON.\nExpected: %s\nGot:      %s", expected, data)
+	}
+
+	var decoded elements.Text
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	props := decoded.Properties.(elements.TextProperties)
+	color, _ := props.BackgroundColor.Value()
+	padding, _ := props.BackgroundXPadding.Value()
+	if props.Text != "Hello" || props.FontFamily != "Roboto" || color != "#fff" || padding != "10%" {
+		t.Errorf("Unexpected properties after unmarshaling: %+v", props)
+	}
+
+	var video elements.Video
+	if err := json.Unmarshal(data, &video); err == nil {
+		t.Error("Expected unmarshaling a text element into a video to fail")
+	}
+
+	var base elements.BaseElement
+	if err := json.Unmarshal([]byte(`{"type":"sparkle","intensity":3}`), &base); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if base.Type != "sparkle" {
+		t.Errorf("Expected the unknown type to be kept, got %q", base.Type)
+	}
+	if data, _ := json.Marshal(base); string(data) != `{"intensity":3,"type":"sparkle"}` {
+		t.Errorf("Expected an unknown element to round trip, got %s", data)
+	}
+}
+
+func TestAnimationAndFontMarshalJSON(t *testing.T) {
+	fade := animations.NewFade(animations.FadeProperties{From: 0, To: 100})
+	data, err := json.Marshal(fade)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"to":100,"type":"fade"}` {
+		t.Errorf("Unexpected animation JSON: %s", data)
+	}
+
+	var decoded animations.Fade
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if props := decoded.Properties.(animations.FadeProperties); props.To != 100 {
+		t.Errorf("Unexpected properties after unmarshaling: %+v", props)
+	}
+
+	font := creatomate.NewFont("Inter", 400)
+	font.Size = "8 vmin"
+	data, _ = json.Marshal(font)
+	if string(data) != `{"font_family":"Inter","font_size":"8 vmin","font_weight":400}` {
+		t.Errorf("Unexpected font JSON: %s", data)
+	}
+	var decodedFont creatomate.Font
+	if err := json.Unmarshal(data, &decodedFont); err != nil || !reflect.DeepEqual(&decodedFont, font) {
+		t.Errorf("Expected the font to round trip, got %#v (%v)", decodedFont, err)
+	}
+}
+
+func TestSourceCanonicalJSON(t *testing.T) {
+	fade := animations.AnimationProperties{Duration: 1}
+	a := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		FrameRate:    30,
+		Elements: []interface{}{
+			elements.NewText(elements.TextProperties{
+				ElementProperties: elements.ElementProperties{
+					Enter: animations.NewFade(animations.FadeProperties{AnimationProperties: fade}),
+					Animations: []interface{}{
+						animations.NewSpin(animations.SpinProperties{AnimationProperties: animations.AnimationProperties{Time: 2}}),
+					},
+				},
+				Text: "<b>Hello</b>",
+			}),
+		},
+	})
+	b := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		FrameRate:    30.0,
+		Elements: []interface{}{
+			map[string]interface{}{
+				"type": "text",
+				"text": "<b>Hello</b>",
+				"x":    nil,
+				"animations": []interface{}{
+					map[string]interface{}{"type": "spin", "time": int64(2)},
+					map[string]interface{}{"type": "fade", "time": "start", "duration": 1.0},
+				},
+			},
+		},
+	})
+
+	aJSON, err := a.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON failed: %v", err)
+	}
+	bJSON, err := b.CanonicalJSON()
+	if err != nil {
+		t.Fatalf("CanonicalJSON failed: %v", err)
+	}
+	expected := `{"elements":[{"animations":[{"duration":1,"time":"start","type":"fade"},{"time":2,"type":"spin"}],"text":"<b>Hello</b>","type":"text"}],"frame_rate":30,"output_format":"mp4"}`
+	if string(aJSON) != expected {
+		t.Errorf("Unexpected canonical JSON.\nExpected: %s\nGot:      %s", expected, aJSON)
+	}
+	if string(aJSON) != string(bJSON) {
+		t.Errorf("Expected equal sources to encode identically.\nFirst:  %s\nSecond: %s", aJSON, bJSON)
+	}
+}
+
+func TestSourceCanonicalJSONAnimationTimes(t *testing.T) {
+	canonicalAnimations := func(list ...interface{}) string {
+		source := creatomate.NewSource(creatomate.SourceProperties{
+			Elements: []interface{}{
+				map[string]interface{}{"type": "shape", "animations": list},
+			},
+		})
+		data, err := source.CanonicalJSON()
+		if err != nil {
+			t.Fatalf("CanonicalJSON failed: %v", err)
+		}
+		return string(data)
+	}
+	animation := func(typ string, time interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": typ, "time": time}
+	}
+
+	cases := []struct {
+		name     string
+		first    []interface{}
+		second   []interface{}
+		expected string
+	}{
+		{
+			"seconds",
+			[]interface{}{animation("spin", "end"), animation("wipe", "2 s"), animation("fade", 1), animation("slide", "start"), animation("pan", "1.5")},
+			[]interface{}{animation("pan", "1.5"), animation("fade", 1), animation("slide", "start"), animation("spin", "end"), animation("wipe", "2 s")},
+			`[{"time":"start","type":"slide"},{"time":1,"type":"fade"},{"time":"1.5","type":"pan"},{"time":"2 s","type":"wipe"},{"time":"end","type":"spin"}]`,
+		},
+		{
+			"percentages",
+			[]interface{}{animation("spin", "75%"), animation("fade", "end"), animation("wipe", "25%")},
+			[]interface{}{animation("fade", "end"), animation("wipe", "25%"), animation("spin", "75%")},
+			`[{"time":"25%","type":"wipe"},{"time":"75%","type":"spin"},{"time":"end","type":"fade"}]`,
+		},
+	}
+	for _, c := range cases {
+		first, second := canonicalAnimations(c.first...), canonicalAnimations(c.second...)
+		if !strings.Contains(first, c.expected) {
+			t.Errorf("%s: expected %s in %s", c.name, c.expected, first)
+		}
+		if first != second {
+			t.Errorf("%s: expected equal sources to encode identically.\nFirst:  %s\nSecond: %s", c.name, first, second)
+		}
+	}
+
+	// Percentages can't be compared with seconds, and unknown times can't be ranked at all, so
+	// they keep their position
+	mixed := canonicalAnimations(animation("spin", "end"), animation("fade", "50%"), animation("wipe", 1), animation("slide", "later"), animation("pan", "start"))
+	expected := `[{"time":"start","type":"pan"},{"time":"50%","type":"fade"},{"time":1,"type":"wipe"},{"time":"later","type":"slide"},{"time":"end","type":"spin"}]`
+	if !strings.Contains(mixed, expected) {
+		t.Errorf("Expected %s in %s", expected, mixed)
+	}
+}
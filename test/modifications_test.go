@@ -0,0 +1,54 @@
+package creatomate_test
+
+import (
+	"errors"
+	"testing"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+	"github.com/Lakeshore-Labs/creatomate-go/properties"
+)
+
+func TestModificationsValidate(t *testing.T) {
+	source := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		Elements: []interface{}{
+			elements.NewVideo(elements.VideoProperties{
+				ElementProperties: elements.ElementProperties{Name: "Background"},
+				Source:            "https://example.com/video.mp4",
+			}),
+			elements.NewComposition(elements.CompositionProperties{
+				Elements: []interface{}{
+					elements.NewText(elements.TextProperties{
+						ElementProperties: elements.ElementProperties{Name: "Title"},
+						Text:              "Hello",
+					}),
+				},
+			}),
+		},
+	})
+
+	valid := creatomate.NewModifications().
+		SetText("Title", "Welcome").
+		Set("Title", "font_size_maximum", "8 vmin").
+		SetSource("Background", "https://example.com/other.mp4")
+	if err := valid.Validate(source); err != nil {
+		t.Errorf("Expected modifications to be valid, got %v", err)
+	}
+	if got := valid.Map()["Title.text"]; got != "Welcome" {
+		t.Errorf("Expected Title.text to be Welcome, got %v", got)
+	}
+
+	invalid := creatomate.NewModifications().
+		SetText("Titel", "Welcome").
+		Set("Background", "text", "Welcome")
+	err := invalid.Validate(source)
+
+	var modErr *creatomate.ModificationError
+	if !errors.As(err, &modErr) || modErr.Key != "Titel.text" {
+		t.Fatalf("Expected an error for Titel.text, got %v", err)
+	}
+	if err.Error() != `modification "Titel.text": unknown element "Titel"`+"\n"+`modification "Background.text": unknown property "text"` {
+		t.Errorf("Unexpected error %q", err)
+	}
+}
@@ -0,0 +1,229 @@
+package creatomate_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/animations"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+)
+
+func TestParseSourceRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("testdata/json-outputs/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("No fixtures found: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+
+			source, err := creatomate.ParseSource(data)
+			if err != nil {
+				t.Fatalf("ParseSource failed: %v", err)
+			}
+
+			var expected, actual map[string]interface{}
+			json.Unmarshal(data, &expected)
+			actualJSON, _ := json.Marshal(source.ToMap())
+			json.Unmarshal(actualJSON, &actual)
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("Round trip doesn't match.\nExpected:\n%s\n\nGot:\n%s", data, actualJSON)
+			}
+		})
+	}
+}
+
+func TestParseSourceFoldsProperties(t *testing.T) {
+	data, err := os.ReadFile("testdata/json-outputs/text-overlay.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	source, err := creatomate.ParseSource(data)
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+
+	text, ok := source.Properties.Elements[1].(*elements.Text)
+	if !ok {
+		t.Fatalf("Expected a text element, got %T", source.Properties.Elements[1])
+	}
+	props := text.Properties.(elements.TextProperties)
+
+	font, ok := props.Font.(*creatomate.Font)
+	if !ok || font.Family != "Open Sans" || font.Weight == nil || *font.Weight != 700 || font.Maximum != "10.4 vmin" {
+		t.Errorf("Expected the font properties to be folded into a Font, got %#v", props.Font)
+	}
+	if props.FontFamily != "" || !props.FontWeight.IsZero() {
+		t.Errorf("Expected the font properties to be moved, got %q and %v", props.FontFamily, props.FontWeight)
+	}
+	if background, ok := props.Background.(*creatomate.TextBackground); !ok || background.Color != "rgba(255,255,255,0.69)" {
+		t.Errorf("Expected the background properties to be folded into a TextBackground, got %#v", props.Background)
+	}
+	if slide, ok := props.Enter.(*creatomate.TextSlide); !ok || slide.Split != "line" || slide.BackgroundEffect != "scaling-clip" {
+		t.Errorf("Expected the start animation to become Enter, got %#v", props.Enter)
+	}
+	if len(props.Animations) != 0 {
+		t.Errorf("Expected no remaining animations, got %v", props.Animations)
+	}
+
+	// Edit and serialize again
+	props.Text = "Edited"
+	text.Properties = props
+	result := source.ToMap()["elements"].([]interface{})[1].(map[string]interface{})
+	if result["text"] != "Edited" || result["font_family"] != "Open Sans" || result["background_color"] != "rgba(255,255,255,0.69)" {
+		t.Errorf("Unexpected text element after editing: %v", result)
+	}
+}
+
+func TestParseSourceKeyframesAndAnimations(t *testing.T) {
+	source, err := creatomate.ParseSource([]byte(`{
+		"output_format": "mp4",
+		"elements": [
+			{
+				"type": "composition",
+				"x": [{"time": 0, "value": "0%"}, {"time": 2, "value": "100%", "easing": "linear"}],
+				"elements": [
+					{
+						"type": "image",
+						"source": "https://example.com/image.jpg",
+						"animations": [
+							{"type": "fade", "time": "start", "duration": 1, "transition": true},
+							{"type": "spin", "time": 2, "duration": 1, "rotations": 2},
+							{"type": "unknown-effect", "time": 3},
+							{"type": "fade", "time": "end", "duration": 1, "reversed": true}
+						]
+					}
+				]
+			}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+
+	composition := source.Properties.Elements[0].(*elements.Composition)
+	compositionProps := composition.Properties.(elements.CompositionProperties)
+	keyframes := compositionProps.X.Keyframes()
+	if len(keyframes) != 2 {
+		t.Fatalf("Expected two keyframes, got %#v", compositionProps.X)
+	}
+	if keyframe := keyframes[1]; keyframe.Time != 2 || keyframe.Value != "100%" || keyframe.Easing != "linear" {
+		t.Errorf("Unexpected keyframe %#v", keyframe)
+	}
+
+	image := compositionProps.Elements[0].(*elements.Image)
+	imageProps := image.Properties.(elements.ImageProperties)
+	if _, ok := imageProps.Transition.(*animations.Fade); !ok {
+		t.Errorf("Expected the transition to become a Fade, got %#v", imageProps.Transition)
+	}
+	if _, ok := imageProps.Exit.(*animations.Fade); !ok {
+		t.Errorf("Expected the exit animation to become a Fade, got %#v", imageProps.Exit)
+	}
+	if len(imageProps.Animations) != 2 {
+		t.Fatalf("Expected two remaining animations, got %v", imageProps.Animations)
+	}
+	if _, ok := imageProps.Animations[0].(*animations.Spin); !ok {
+		t.Errorf("Expected a Spin, got %#v", imageProps.Animations[0])
+	}
+	if _, ok := imageProps.Animations[1].(map[string]interface{}); !ok {
+		t.Errorf("Expected the unknown animation to stay a map, got %#v", imageProps.Animations[1])
+	}
+
+	result := source.ToMap()["elements"].([]interface{})[0].(map[string]interface{})["elements"].([]interface{})[0].(map[string]interface{})
+	resultJSON, _ := json.Marshal(result["animations"])
+	expected := `[{"duration":1,"time":"start","transition":true,"type":"fade"},{"duration":1,"rotations":2,"time":2,"type":"spin"},{"time":3,"type":"unknown-effect"},{"duration":1,"reversed":true,"time":"end","type":"fade"}]`
+	if string(resultJSON) != expected {
+		t.Errorf("Animations don't round trip.\nExpected: %s\nGot:      %s", expected, resultJSON)
+	}
+}
+
+func TestParseSourceKeepsUnknownElementProperties(t *testing.T) {
+	data := []byte(`{
+		"output_format": "mp4",
+		"elements": [
+			{
+				"type": "composition",
+				"name": "Scene",
+				"locked": true,
+				"elements": [
+					{"type": "text", "text": "Hello", "dynamic": true, "x_custom": {"a": 1}}
+				]
+			}
+		]
+	}`)
+	source, err := creatomate.ParseSource(data)
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+
+	composition := source.Properties.Elements[0].(*elements.Composition)
+	text := composition.Properties.(elements.CompositionProperties).Elements[0].(*elements.Text)
+	if extra := text.Properties.(elements.TextProperties).Extra; extra["dynamic"] != true || len(extra) != 2 {
+		t.Errorf("Expected the unknown properties in Extra, got %v", extra)
+	}
+
+	var expected, actual map[string]interface{}
+	json.Unmarshal(data, &expected)
+	actualJSON, _ := json.Marshal(source)
+	json.Unmarshal(actualJSON, &actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Unknown properties don't round trip.\nExpected:\n%s\n\nGot:\n%s", data, actualJSON)
+	}
+}
+
+func TestParseSourceKeepsAnimatedTextProperties(t *testing.T) {
+	data := []byte(`{
+		"output_format": "mp4",
+		"elements": [
+			{
+				"type": "text",
+				"text": "Hello",
+				"font_family": "Open Sans",
+				"font_size_maximum": [{"time": 0, "value": "8 vmin"}, {"time": 1, "value": "10 vmin"}],
+				"background_color": "#fff",
+				"background_x_padding": [{"time": 0, "value": "10%"}, {"time": 1, "value": "20%"}]
+			}
+		]
+	}`)
+	source, err := creatomate.ParseSource(data)
+	if err != nil {
+		t.Fatalf("ParseSource failed: %v", err)
+	}
+
+	props := source.Properties.Elements[0].(*elements.Text).Properties.(elements.TextProperties)
+	if props.Font != nil || len(props.FontSizeMaximum.Keyframes()) != 2 {
+		t.Errorf("Expected the animated font size to stay in FontSizeMaximum, got %#v and %#v", props.Font, props.FontSizeMaximum)
+	}
+	if props.Background != nil || len(props.BackgroundXPadding.Keyframes()) != 2 {
+		t.Errorf("Expected the animated padding to stay in BackgroundXPadding, got %#v and %#v", props.Background, props.BackgroundXPadding)
+	}
+
+	var expected, actual map[string]interface{}
+	json.Unmarshal(data, &expected)
+	actualJSON, _ := json.Marshal(source)
+	json.Unmarshal(actualJSON, &actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Animated text properties don't round trip.\nExpected:\n%s\n\nGot:\n%s", data, actualJSON)
+	}
+}
+
+func TestAnimationStructToMap(t *testing.T) {
+	// Animations built from property structs rather than maps keep their properties
+	fade := animations.NewFade(animations.FadeProperties{
+		AnimationProperties: animations.AnimationProperties{Duration: 1, Easing: "linear"},
+		To:                  50,
+	})
+	data, _ := json.Marshal(fade.ToMap())
+	expected := `{"duration":1,"easing":"linear","to":50,"type":"fade"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
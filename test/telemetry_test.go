@@ -0,0 +1,97 @@
+package creatomate_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/creatomatetest"
+)
+
+func TestTelemetryRecordsRenderLifecycle(t *testing.T) {
+	server := creatomatetest.NewServer(creatomatetest.WithProgression(
+		creatomate.RenderStatusPlanned,
+		creatomate.RenderStatusRendering,
+		creatomate.RenderStatusSucceeded,
+	))
+	defer server.Close()
+
+	telemetry := newTestTelemetry("creatomate_test_lifecycle")
+	client := server.NewClient(
+		creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)),
+		creatomate.WithTelemetry(telemetry),
+	)
+
+	if _, err := client.Render(context.Background(), creatomate.RenderOptions{TemplateID: "template-1"}, time.Minute); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	for _, key := range []string{
+		creatomate.MetricRenderDuration,
+		creatomate.MetricTimeToStart,
+		creatomate.MetricStatusDuration + "{status=planned}",
+		creatomate.MetricStatusDuration + "{status=rendering}",
+		creatomate.SpanRender + ".span.duration",
+	} {
+		var histogram struct {
+			Count int `json:"count"`
+		}
+		v := telemetry.Vars().Get(key)
+		if v == nil {
+			t.Errorf("Expected metric %s to be recorded", key)
+			continue
+		}
+		if err := json.Unmarshal([]byte(v.String()), &histogram); err != nil || histogram.Count != 1 {
+			t.Errorf("Expected metric %s to have one observation, got %s", key, v.String())
+		}
+	}
+	if v := telemetry.Vars().Get(creatomate.MetricFailures + "{error_type=timeout}"); v != nil {
+		t.Errorf("Expected no failures, got %s", v.String())
+	}
+}
+
+func TestTelemetryCountsFailuresByType(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+
+	telemetry := newTestTelemetry("creatomate_test_failures")
+	client := server.NewClient(creatomate.WithTelemetry(telemetry))
+
+	server.FailNext(creatomate.RouteStartRender, http.StatusBadRequest, "Invalid source")
+	if _, err := client.Render(context.Background(), creatomate.RenderOptions{TemplateID: "template-1"}, time.Minute); err == nil {
+		t.Fatal("Expected Render to fail")
+	}
+
+	key := creatomate.MetricFailures + "{error_type=bad_request}"
+	if v := telemetry.Vars().Get(key); v == nil || v.String() != "1" {
+		t.Errorf("Expected %s to be 1, got %v", key, v)
+	}
+	if v := telemetry.Vars().Get(creatomate.SpanRender + ".span.errors"); v == nil || v.String() != "1" {
+		t.Errorf("Expected the render span to record an error, got %v", v)
+	}
+}
+
+func TestExpvarTelemetrySharesMetricsByName(t *testing.T) {
+	name := fmt.Sprintf("creatomate_test_shared_%d", time.Now().UnixNano())
+	first := creatomate.NewExpvarTelemetry(name)
+	second := creatomate.NewExpvarTelemetry(name)
+	if first.Vars() != second.Vars() {
+		t.Fatal("Expected telemetries with the same name to share the published map")
+	}
+
+	first.Count(creatomate.MetricFailures, 1)
+	second.Count(creatomate.MetricFailures, 2)
+	if v := first.Vars().Get(creatomate.MetricFailures); v == nil || v.String() != "3" {
+		t.Errorf("Expected the counts to add up to 3, got %v", v)
+	}
+}
+
+// newTestTelemetry creates a telemetry under a name of its own, since metrics published under
+// the same name add up across test runs with -count.
+func newTestTelemetry(prefix string) *creatomate.ExpvarTelemetry {
+	return creatomate.NewExpvarTelemetry(fmt.Sprintf("%s_%d", prefix, time.Now().UnixNano()))
+}
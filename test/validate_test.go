@@ -0,0 +1,155 @@
+package creatomate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/creatomatetest"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+	"github.com/Lakeshore-Labs/creatomate-go/properties"
+)
+
+func validationPaths(t *testing.T, err error) map[string]bool {
+	t.Helper()
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Expected joined validation errors, got %v", err)
+	}
+	paths := make(map[string]bool)
+	for _, err := range joined.Unwrap() {
+		var validationError *creatomate.ValidationError
+		if !errors.As(err, &validationError) {
+			t.Fatalf("Expected a *ValidationError, got %T", err)
+		}
+		paths[validationError.Path] = true
+	}
+	return paths
+}
+
+func TestSourceValidate(t *testing.T) {
+	valid := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatGIF,
+		GifQuality:   properties.GifQualityBest,
+		Loop:         true,
+		Elements: []interface{}{
+			elements.NewVideo(elements.VideoProperties{Source: "https://example.com/video.mp4", Fit: properties.FitCover}),
+		},
+	})
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected a valid source, got %v", err)
+	}
+
+	invalid := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		CRF:          12,
+		Loop:         true,
+		Elements: []interface{}{
+			elements.NewImage(elements.ImageProperties{}),
+			elements.NewComposition(elements.CompositionProperties{
+				Elements: []interface{}{
+					elements.NewText(elements.TextProperties{Text: "Hello", TextTransform: "shout"}),
+					map[string]interface{}{
+						"type": "shape",
+						"blend_mode": []interface{}{
+							map[string]interface{}{"time": 0, "value": "normal"},
+							map[string]interface{}{"time": 1, "value": "sparkle"},
+						},
+					},
+				},
+			}),
+		},
+	})
+
+	err := invalid.Validate()
+	paths := validationPaths(t, err)
+	expected := []string{
+		"crf",
+		"loop",
+		"elements[0].source",
+		"elements[1].elements[0].text_transform",
+		"elements[1].elements[1].blend_mode[1].value",
+	}
+	for _, path := range expected {
+		if !paths[path] {
+			t.Errorf("Expected an error for %s, got %v", path, err)
+		}
+	}
+	if len(paths) != len(expected) {
+		t.Errorf("Expected %d errors, got %v", len(expected), err)
+	}
+}
+
+func TestStartRenderWithSourceValidation(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+	client := server.NewClient(creatomate.WithSourceValidation())
+
+	_, err := client.StartRender(context.Background(), creatomate.RenderOptions{
+		Source: map[string]interface{}{"output_format": "webm", "gif_compression": 300},
+	})
+	paths := validationPaths(t, err)
+	if !paths["output_format"] || !paths["gif_compression"] || len(paths) != 2 {
+		t.Errorf("Unexpected validation errors: %v", err)
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("Expected no request for an invalid source, got %d", len(server.Requests()))
+	}
+
+	_, err = client.StartRender(context.Background(), creatomate.RenderOptions{
+		Source: creatomate.NewSource(creatomate.SourceProperties{OutputFormat: properties.OutputFormatMP4, CRF: 23}),
+	})
+	if err != nil {
+		t.Errorf("Expected a valid source to render, got %v", err)
+	}
+}
+
+func TestStartRenderValidatesSourcesOfAnyType(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+	client := server.NewClient(creatomate.WithSourceValidation())
+
+	invalid := `{"output_format":"mp4","crf":80}`
+	for _, source := range []interface{}{
+		*creatomate.NewSource(creatomate.SourceProperties{OutputFormat: properties.OutputFormatMP4, CRF: 80}),
+		json.RawMessage(invalid),
+		[]byte(invalid),
+		invalid,
+	} {
+		_, err := client.StartRender(context.Background(), creatomate.RenderOptions{Source: source})
+		if paths := validationPaths(t, err); !paths["crf"] || len(paths) != 1 {
+			t.Errorf("Expected the CRF of the %T source to be invalid, got %v", source, err)
+		}
+	}
+	if len(server.Requests()) != 0 {
+		t.Errorf("Expected no request for invalid sources, got %d", len(server.Requests()))
+	}
+}
+
+func TestStartRenderValidationAllowsModifiedSources(t *testing.T) {
+	server := creatomatetest.NewServer()
+	defer server.Close()
+	client := server.NewClient(creatomate.WithSourceValidation())
+
+	source := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		Elements: []interface{}{
+			elements.NewVideo(elements.VideoProperties{ElementProperties: elements.ElementProperties{Name: "Background"}}),
+			elements.NewImage(elements.ImageProperties{ElementProperties: elements.ElementProperties{Name: "Logo"}}),
+			elements.NewAudio(elements.AudioProperties{ElementProperties: elements.ElementProperties{Name: "Music"}}),
+		},
+	})
+	_, err := client.StartRender(context.Background(), creatomate.RenderOptions{
+		Source: source,
+		Modifications: creatomate.NewModifications().
+			SetSource("Background", "https://example.com/video.mp4").
+			Set("Logo", "", "https://example.com/logo.png").
+			Set("Music", "volume", "50%").
+			Map(),
+	})
+	if paths := validationPaths(t, err); !paths["elements[2].source"] || len(paths) != 1 {
+		t.Errorf("Expected only the unmodified element to need a source, got %v", err)
+	}
+}
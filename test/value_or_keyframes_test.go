@@ -0,0 +1,157 @@
+package creatomate_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/elements"
+	"github.com/Lakeshore-Labs/creatomate-go/properties"
+)
+
+func TestValueOrKeyframesJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"static", creatomate.Static[any]("50%"), `"50%"`},
+		{"static zero", creatomate.Static(0), `0`},
+		{"unset", properties.ValueOrKeyframes[string]{}, `null`},
+		{"animated", creatomate.Animated(
+			creatomate.NewKeyframe("#000", 0),
+			creatomate.NewKeyframeWithEasing("#fff", 2, properties.EasingLinear),
+		), `[{"time":0,"value":"#000"},{"time":2,"value":"#fff","easing":"linear"}]`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(c.value)
+		if err != nil {
+			t.Fatalf("%s: Marshal failed: %v", c.name, err)
+		}
+		if string(data) != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.name, c.expected, data)
+		}
+	}
+
+	var animated properties.ValueOrKeyframes[string]
+	if err := json.Unmarshal([]byte(`[{"time":0,"value":"#000"},{"time":2,"value":"#fff"}]`), &animated); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if keyframes := animated.Keyframes(); len(keyframes) != 2 || keyframes[1].Value != "#fff" {
+		t.Errorf("Expected two keyframes, got %#v", keyframes)
+	}
+	if err := json.Unmarshal([]byte(`[{"time":0,"value":1}]`), &animated); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if animated.Keyframes() != nil || string(animated.Raw()) != `[{"time":0,"value":1}]` {
+		t.Errorf("Expected keyframes of the wrong type to be kept as raw JSON, got %#v", animated)
+	}
+
+	var matrix properties.ValueOrKeyframes[[][]properties.WarpPoint]
+	if err := json.Unmarshal([]byte(`[[{"x":0,"y":0}]]`), &matrix); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if value, ok := matrix.Value(); !ok || len(value) != 1 {
+		t.Errorf("Expected an array to be a static value, got %#v", matrix)
+	}
+}
+
+func TestValueOrKeyframesToMap(t *testing.T) {
+	shape := elements.NewShape(elements.ShapeProperties{
+		ElementProperties: elements.ElementProperties{
+			X:      creatomate.Static[any](0),
+			ZIndex: creatomate.Static(2),
+			XScale: creatomate.Animated(
+				creatomate.NewKeyframe[any]("20%", 0),
+				creatomate.NewKeyframe[any](1.5, 2),
+			),
+		},
+		FillColor: creatomate.Static[any]("#333"),
+	})
+
+	data, _ := json.Marshal(shape.ToMap())
+	expected := `{"fill_color":"#333","type":"shape","x":0,"x_scale":[{"time":0,"value":"20%"},{"time":2,"value":1.5}],"z_index":2}`
+	if string(data) != expected {
+		t.Errorf("Unexpected JSON.\nExpected: %s\nGot:      %s", expected, data)
+	}
+}
+
+func TestValueOrKeyframesValidate(t *testing.T) {
+	ordered := creatomate.Animated(creatomate.NewKeyframe(0.0, 0), creatomate.NewKeyframe(1.0, 1), creatomate.NewKeyframe(2.0, 1))
+	if err := ordered.Validate(); err != nil {
+		t.Errorf("Expected ordered keyframes to be valid, got %v", err)
+	}
+	unordered := creatomate.Animated(creatomate.NewKeyframe(0.0, 2), creatomate.NewKeyframe(1.0, 1))
+	if err := unordered.Validate(); err == nil {
+		t.Error("Expected unordered keyframes to be invalid")
+	}
+
+	source := creatomate.NewSource(creatomate.SourceProperties{
+		OutputFormat: properties.OutputFormatMP4,
+		Elements: []interface{}{
+			elements.NewText(elements.TextProperties{
+				Text: "Hello",
+				Color: creatomate.Animated(
+					creatomate.NewKeyframe("#000", 2),
+					creatomate.NewKeyframe("#fff", 1),
+				),
+			}),
+		},
+	})
+	paths := validationPaths(t, source.Validate())
+	if !paths["elements[0].color[1].time"] || len(paths) != 1 {
+		t.Errorf("Expected an error for the unordered keyframe, got %v", paths)
+	}
+}
+
+func TestParseSourceNumericStrings(t *testing.T) {
+	cases := []struct {
+		name     string
+		element  string
+		expected string
+	}{
+		{"font_weight", `{"type":"text","font_weight":"700"}`, `"font_weight":700`},
+		{"z_index", `{"type":"shape","z_index":"2"}`, `"z_index":2`},
+		{"aspect_ratio", `{"type":"image","source":"https://example.com/image.jpg","aspect_ratio":"1"}`, `"aspect_ratio":1`},
+		{"keyframes", `{"type":"shape","z_index":[{"time":0,"value":"1"},{"time":1,"value":3}]}`, `"z_index":[{"time":0,"value":1},{"time":1,"value":3}]`},
+	}
+	for _, c := range cases {
+		source, err := creatomate.ParseSource([]byte(`{"output_format":"mp4","elements":[` + c.element + `]}`))
+		if err != nil {
+			t.Errorf("%s: ParseSource failed: %v", c.name, err)
+			continue
+		}
+		data, _ := json.Marshal(source)
+		if !strings.Contains(string(data), c.expected) {
+			t.Errorf("%s: expected %s in %s", c.name, c.expected, data)
+		}
+	}
+}
+
+func TestParseSourceKeepsValuesOfOtherTypes(t *testing.T) {
+	elements := []string{
+		`{"type":"shape","color_filter_value":"50%","blur_radius":"2 vmin","clip":"true"}`,
+		`{"type":"shape","z_index":"front","shadow_color":null}`,
+		`{"type":"shape","z_index":"null","x_scale":[{"time":0,"value":"20%"}]}`,
+		`{"type":"image","source":"https://example.com/image.jpg","aspect_ratio":"16:9"}`,
+		`{"type":"text","text":"Hello","font_family":"Open Sans","font_weight":"bold"}`,
+	}
+	for _, element := range elements {
+		data := []byte(`{"output_format":"mp4","elements":[` + element + `]}`)
+		source, err := creatomate.ParseSource(data)
+		if err != nil {
+			t.Errorf("%s: ParseSource failed: %v", element, err)
+			continue
+		}
+
+		var expected, actual map[string]interface{}
+		json.Unmarshal(data, &expected)
+		actualJSON, _ := json.Marshal(source)
+		json.Unmarshal(actualJSON, &actual)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Round trip doesn't match.\nExpected: %s\nGot:      %s", data, actualJSON)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package creatomate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+)
+
+// progressionServer serves renders that advance one status per fetch.
+func progressionServer(t *testing.T, statuses ...creatomate.RenderStatus) (*httptest.Server, map[string]int) {
+	var mu sync.Mutex
+	fetches := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			json.NewEncoder(w).Encode([]creatomate.Render{
+				{ID: "a", Status: statuses[0]},
+				{ID: "b", Status: statuses[0]},
+			})
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/renders/")
+		fetches[id]++
+		index := fetches[id]
+		if index >= len(statuses) {
+			index = len(statuses) - 1
+		}
+		json.NewEncoder(w).Encode(creatomate.Render{ID: id, Status: statuses[index], URL: "https://cdn.example.com/" + id})
+	}))
+	t.Cleanup(server.Close)
+	return server, fetches
+}
+
+func TestWatchEmitsStatusTransitions(t *testing.T) {
+	server, _ := progressionServer(t, creatomate.RenderStatusPlanned, creatomate.RenderStatusPlanned, creatomate.RenderStatusRendering, creatomate.RenderStatusSucceeded)
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)),
+	)
+
+	var transitions []string
+	for event := range client.Watch(context.Background(), "a") {
+		if event.Err != nil {
+			t.Fatalf("Watch failed: %v", event.Err)
+		}
+		transitions = append(transitions, string(event.PreviousStatus)+"->"+string(event.Render.Status))
+	}
+
+	expected := "->planned,planned->rendering,rendering->succeeded"
+	if got := strings.Join(transitions, ","); got != expected {
+		t.Errorf("Expected transitions %s, got %s", expected, got)
+	}
+}
+
+func TestWatchEndsOnErrorsAndContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/renders/")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(creatomate.Render{ID: id, Status: creatomate.RenderStatusRendering})
+	}))
+	defer server.Close()
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.FixedInterval(time.Millisecond)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var failed, rendering []string
+	for event := range client.Watch(ctx, "missing", "slow") {
+		switch {
+		case event.Err != nil:
+			failed = append(failed, event.Render.ID)
+		case event.Render.Status == creatomate.RenderStatusRendering:
+			rendering = append(rendering, event.Render.ID)
+			// The render never finishes, so stop watching it
+			cancel()
+		}
+	}
+
+	if len(failed) != 1 || failed[0] != "missing" {
+		t.Errorf("Expected a single error for the missing render, got %v", failed)
+	}
+	if len(rendering) != 1 || rendering[0] != "slow" {
+		t.Errorf("Expected a single event for the slow render, got %v", rendering)
+	}
+}
+
+func TestRenderWithPollBudget(t *testing.T) {
+	server, fetches := progressionServer(t, creatomate.RenderStatusPlanned, creatomate.RenderStatusRendering, creatomate.RenderStatusSucceeded)
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.ExponentialInterval{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2}),
+		creatomate.WithPollBudget(1),
+	)
+
+	renders, err := client.Render(context.Background(), creatomate.RenderOptions{TemplateID: "tpl"}, time.Minute)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if len(renders) != 2 || renders[0].Status != creatomate.RenderStatusSucceeded || renders[1].Status != creatomate.RenderStatusSucceeded {
+		t.Errorf("Expected two succeeded renders, got %+v", renders)
+	}
+	if fetches["a"] != 2 || fetches["b"] != 2 {
+		t.Errorf("Expected each render to be fetched twice, got %v", fetches)
+	}
+}
+
+func TestPollBudgetIsSharedByWatches(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		id := strings.TrimPrefix(r.URL.Path, "/renders/")
+		json.NewEncoder(w).Encode(creatomate.Render{ID: id, Status: creatomate.RenderStatusRendering})
+	}))
+	defer server.Close()
+
+	client := creatomate.NewClient("test-key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.FixedInterval(200*time.Millisecond)),
+		creatomate.WithPollBudget(1),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.Watch(ctx, "a")
+	client.Watch(ctx, "b")
+
+	time.Sleep(100 * time.Millisecond)
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Expected the watches to share a budget of one fetch per round, got %d fetches", n)
+	}
+}
+
+func TestRenderPerRenderTimeout(t *testing.T) {
+	server, _ := progressionServer(t,
+		creatomate.RenderStatusPlanned,
+		creatomate.RenderStatusRendering,
+		creatomate.RenderStatusRendering,
+		creatomate.RenderStatusRendering,
+		creatomate.RenderStatusSucceeded,
+	)
+	client := creatomate.NewClient("key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.FixedInterval(5*time.Millisecond)),
+	)
+
+	renders, err := client.Render(context.Background(), creatomate.RenderOptions{
+		TemplateID: "template-1",
+		PerRenderTimeout: func(render creatomate.Render) time.Duration {
+			if render.ID == "b" {
+				return time.Millisecond
+			}
+			return 0
+		},
+	}, time.Minute)
+
+	var timeoutError *creatomate.TimeoutError
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("Expected a TimeoutError, got %v", err)
+	}
+	if ids := timeoutError.IDs(); len(ids) != 1 || ids[0] != "b" {
+		t.Errorf("Expected render b to time out, got %v", ids)
+	}
+	if timeoutError.Renders[0].Status == "" {
+		t.Error("Expected the last known state of the unfinished render")
+	}
+	if len(renders) != 1 || renders[0].ID != "a" || renders[0].Status != creatomate.RenderStatusSucceeded {
+		t.Errorf("Expected render a to finish, got %+v", renders)
+	}
+}
+
+func TestRenderStopsPollingExpiredRenders(t *testing.T) {
+	statuses := []creatomate.RenderStatus{creatomate.RenderStatusPlanned}
+	for i := 0; i < 10; i++ {
+		statuses = append(statuses, creatomate.RenderStatusRendering)
+	}
+	server, fetches := progressionServer(t, append(statuses, creatomate.RenderStatusSucceeded)...)
+	client := creatomate.NewClient("key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.FixedInterval(5*time.Millisecond)),
+	)
+
+	_, err := client.Render(context.Background(), creatomate.RenderOptions{
+		TemplateID: "template-1",
+		PerRenderTimeout: func(render creatomate.Render) time.Duration {
+			if render.ID == "b" {
+				return time.Millisecond
+			}
+			return 0
+		},
+	}, time.Minute)
+
+	var timeoutError *creatomate.TimeoutError
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("Expected a TimeoutError, got %v", err)
+	}
+	if fetches["a"] != len(statuses) || fetches["b"] > 1 {
+		t.Errorf("Expected only render a to be polled after b expired, got %v", fetches)
+	}
+}
+
+func TestRenderMaxTimeout(t *testing.T) {
+	server, _ := progressionServer(t, creatomate.RenderStatusPlanned, creatomate.RenderStatusRendering)
+	client := creatomate.NewClient("key",
+		creatomate.WithBaseURL(server.URL),
+		creatomate.WithPollStrategy(creatomate.FixedInterval(5*time.Millisecond)),
+		creatomate.WithMaxRenderTimeout(20*time.Millisecond),
+	)
+
+	start := time.Now()
+	_, err := client.Render(context.Background(), creatomate.RenderOptions{TemplateID: "template-1"}, time.Hour)
+	var timeoutError *creatomate.TimeoutError
+	if !errors.As(err, &timeoutError) {
+		t.Fatalf("Expected a TimeoutError, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the timeout to be limited, took %v", elapsed)
+	}
+	if ids := timeoutError.IDs(); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("Expected both renders to time out in order, got %v", ids)
+	}
+	if !strings.Contains(err.Error(), "a, b") {
+		t.Errorf("Expected the error to name the renders, got %q", err.Error())
+	}
+}
+
+func TestPollStrategiesZeroValues(t *testing.T) {
+	strategies := map[string]creatomate.PollStrategy{
+		"FixedInterval":       creatomate.FixedInterval(0),
+		"ExponentialInterval": creatomate.ExponentialInterval{},
+		"DurationInterval":    creatomate.DurationInterval{Factor: 0.5},
+	}
+	for name, strategy := range strategies {
+		for round := 1; round <= 3; round++ {
+			if interval := strategy.Interval(round, []creatomate.Render{{ID: "a"}}); interval != 2*time.Second {
+				t.Errorf("%s: expected the default interval in round %d, got %v", name, round, interval)
+			}
+		}
+	}
+
+	duration := creatomate.DurationInterval{Factor: 0.5}
+	if interval := duration.Interval(1, []creatomate.Render{{ID: "a", Duration: 10}}); interval != 5*time.Second {
+		t.Errorf("Expected the interval to follow the duration, got %v", interval)
+	}
+}
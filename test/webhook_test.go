@@ -0,0 +1,240 @@
+package creatomate_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+	"github.com/Lakeshore-Labs/creatomate-go/webhook"
+)
+
+func TestWebhookHandlerDispatch(t *testing.T) {
+	var succeeded, failed []string
+	handler := &webhook.Handler{
+		OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+			succeeded = append(succeeded, render.ID)
+			return nil
+		},
+		OnFailed: func(ctx context.Context, render creatomate.Render) error {
+			failed = append(failed, render.ID)
+			return nil
+		},
+	}
+
+	body := `[{"id":"a","status":"succeeded","url":"https://cdn.example.com/a.mp4"},{"id":"b","status":"failed","error_message":"boom"}]`
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", recorder.Code, recorder.Body)
+	}
+	if len(succeeded) != 1 || succeeded[0] != "a" || len(failed) != 1 || failed[0] != "b" {
+		t.Errorf("Unexpected dispatch: succeeded=%v failed=%v", succeeded, failed)
+	}
+}
+
+func TestWebhookHandlerStatusCodes(t *testing.T) {
+	handler := &webhook.Handler{
+		OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+			return errors.New("database unavailable")
+		},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+	}{
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"invalid json", http.MethodPost, "{", http.StatusBadRequest},
+		{"missing url", http.MethodPost, `{"id":"a","status":"succeeded"}`, http.StatusBadRequest},
+		{"callback error", http.MethodPost, `{"id":"a","status":"succeeded","url":"https://cdn.example.com/a.mp4"}`, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, "/webhook", strings.NewReader(tt.body)))
+		if recorder.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, recorder.Code)
+		}
+	}
+}
+
+func TestWebhookHandlerSignedMetadata(t *testing.T) {
+	secret := []byte("webhook-secret")
+	var metadata []string
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+			metadata = append(metadata, render.Metadata)
+			return nil
+		},
+	}
+
+	deliver := func(token string) int {
+		body := `{"id":"a","status":"succeeded","url":"https://cdn.example.com/a.mp4","metadata":"` + token + `"}`
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	token, err := creatomate.SignMetadata(secret, "order-42", time.Now())
+	if err != nil {
+		t.Fatalf("SignMetadata failed: %v", err)
+	}
+	forged, _ := creatomate.SignMetadata([]byte("wrong-secret"), "order-42", time.Now())
+	stale, _ := creatomate.SignMetadata(secret, "order-42", time.Now().Add(-48*time.Hour))
+
+	if status := deliver(token); status != http.StatusNoContent {
+		t.Errorf("Expected signed delivery to be accepted, got %d", status)
+	}
+	if status := deliver(token); status != http.StatusNoContent {
+		t.Errorf("Expected replayed delivery to be acknowledged, got %d", status)
+	}
+	if status := deliver(forged); status != http.StatusUnauthorized {
+		t.Errorf("Expected forged delivery to be rejected, got %d", status)
+	}
+	if status := deliver(stale); status != http.StatusUnauthorized {
+		t.Errorf("Expected stale delivery to be rejected, got %d", status)
+	}
+	if status := deliver("order-42"); status != http.StatusUnauthorized {
+		t.Errorf("Expected unsigned delivery to be rejected, got %d", status)
+	}
+	if len(metadata) != 1 || metadata[0] != "order-42" {
+		t.Errorf("Expected original metadata to be restored once, got %v", metadata)
+	}
+}
+
+func TestWebhookHandlerSharedToken(t *testing.T) {
+	secret := []byte("webhook-secret")
+	var ids []string
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+			ids = append(ids, render.ID)
+			return nil
+		},
+	}
+	token, _ := creatomate.SignMetadata(secret, "order-42", time.Now())
+
+	deliver := func(id string, status creatomate.RenderStatus) int {
+		body := `{"id":"` + id + `","status":"` + string(status) + `","url":"https://cdn.example.com/` + id + `.mp4","metadata":"` + token + `"}`
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	// Progress deliveries don't use up the token of the render
+	if status := deliver("a", creatomate.RenderStatusRendering); status != http.StatusNoContent {
+		t.Errorf("Expected the progress delivery to be accepted, got %d", status)
+	}
+	if status := deliver("a", creatomate.RenderStatusSucceeded); status != http.StatusNoContent {
+		t.Errorf("Expected the first delivery to be accepted, got %d", status)
+	}
+	if status := deliver("b", creatomate.RenderStatusSucceeded); status != http.StatusNoContent {
+		t.Errorf("Expected another render of the same start to be accepted, got %d", status)
+	}
+	if status := deliver("a", creatomate.RenderStatusSucceeded); status != http.StatusNoContent {
+		t.Errorf("Expected the replay to be acknowledged, got %d", status)
+	}
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("Expected every render to be dispatched once, got %v", ids)
+	}
+}
+
+func TestWebhookHandlerReleasesFailedDeliveries(t *testing.T) {
+	secret := []byte("webhook-secret")
+	callbackErr := errors.New("database unavailable")
+	calls := 0
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+			calls++
+			if calls == 1 {
+				return callbackErr
+			}
+			return nil
+		},
+	}
+	token, _ := creatomate.SignMetadata(secret, "order-42", time.Now())
+	body := `{"id":"a","status":"succeeded","url":"https://cdn.example.com/a.mp4","metadata":"` + token + `"}`
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(recorder.Body.String(), callbackErr.Error()) {
+		t.Errorf("Expected the callback error, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+	if recorder.Code != http.StatusNoContent || calls != 2 {
+		t.Errorf("Expected the retried delivery to be dispatched, got %d after %d calls", recorder.Code, calls)
+	}
+}
+
+type fetcherFunc func(ctx context.Context, id string) (*creatomate.Render, error)
+
+func (f fetcherFunc) FetchRender(ctx context.Context, id string) (*creatomate.Render, error) {
+	return f(ctx, id)
+}
+
+func TestWebhookHandlerFetcher(t *testing.T) {
+	secret := []byte("webhook-secret")
+	token, _ := creatomate.SignMetadata(secret, "order-42", time.Now())
+	other, _ := creatomate.SignMetadata(secret, "order-43", time.Now())
+
+	// Two renders from the same start, and one from another start
+	stored := map[string]creatomate.Render{
+		"a": {ID: "a", Status: creatomate.RenderStatusSucceeded, URL: "https://cdn.example.com/a.mp4", Metadata: token},
+		"b": {ID: "b", Status: creatomate.RenderStatusSucceeded, URL: "https://cdn.example.com/b.mp4", Metadata: token},
+		"c": {ID: "c", Status: creatomate.RenderStatusFailed, Metadata: other},
+	}
+	var dispatched []creatomate.Render
+	handler := &webhook.Handler{
+		Secret: secret,
+		Fetcher: fetcherFunc(func(ctx context.Context, id string) (*creatomate.Render, error) {
+			render, ok := stored[id]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+			return &render, nil
+		}),
+		OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+			dispatched = append(dispatched, render)
+			return nil
+		},
+	}
+
+	deliver := func(id, url string) int {
+		body := `{"id":"` + id + `","status":"succeeded","url":"` + url + `","metadata":"` + token + `"}`
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	if status := deliver("a", "https://evil.example.com/a.mp4"); status != http.StatusNoContent {
+		t.Errorf("Expected the delivery to be accepted, got %d", status)
+	}
+	if status := deliver("b", "https://cdn.example.com/b.mp4"); status != http.StatusNoContent {
+		t.Errorf("Expected the second render of the start to be accepted, got %d", status)
+	}
+	if status := deliver("a", "https://cdn.example.com/a.mp4"); status != http.StatusNoContent {
+		t.Errorf("Expected the replay to be acknowledged, got %d", status)
+	}
+	if status := deliver("c", "https://cdn.example.com/c.mp4"); status != http.StatusUnauthorized {
+		t.Errorf("Expected a render of another start to be rejected, got %d", status)
+	}
+	if status := deliver("d", "https://cdn.example.com/d.mp4"); status != http.StatusInternalServerError {
+		t.Errorf("Expected an unknown render to fail, got %d", status)
+	}
+
+	if len(dispatched) != 2 || dispatched[0].URL != "https://cdn.example.com/a.mp4" || dispatched[0].Metadata != "order-42" {
+		t.Errorf("Expected the fetched renders to be dispatched, got %+v", dispatched)
+	}
+}
@@ -1,6 +1,7 @@
 package utility
 
 import (
+	"encoding/json"
 	"strings"
 	"unicode"
 )
@@ -46,9 +47,10 @@ func TransformObjectKeysToSnake(obj interface{}) map[string]interface{} {
 			result[CamelToSnakeCase(key)] = value
 		}
 	default:
-		// Use reflection for structs
-		// This is a simplified version - in production would use reflection
-		// For now, the caller should handle struct to map conversion
+		// Structs already carry snake_case names in their JSON tags
+		if data, err := json.Marshal(v); err == nil {
+			json.Unmarshal(data, &result)
+		}
 	}
 	
 	return result
@@ -0,0 +1,173 @@
+package creatomate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = 2 * time.Second
+
+// RenderEvent is emitted by Watch whenever the status of a render changes.
+type RenderEvent struct {
+	// The latest known state of the render.
+	Render Render
+
+	// The status before this change, or an empty string for the first event of a render.
+	PreviousStatus RenderStatus
+
+	// Set when the render could not be fetched. Render.ID identifies the render. Temporary
+	// errors are followed by further polling, other errors end the watch of that render.
+	Err error
+
+	// The time at which the change was observed.
+	Time time.Time
+}
+
+// Done reports whether the render has finished, either successfully or not.
+func (e RenderEvent) Done() bool {
+	return e.Err == nil && !e.Render.Status.InProgress()
+}
+
+// Watch polls the renders with the given IDs and emits an event for every status change,
+// e.g. planned → rendering → succeeded. The channel is closed when all renders have
+// finished or the context is done.
+func (c *Client) Watch(ctx context.Context, ids ...string) <-chan RenderEvent {
+	renders := make([]Render, len(ids))
+	for i, id := range ids {
+		renders[i] = Render{ID: id}
+	}
+	return c.watch(ctx, renders, nil)
+}
+
+// watch polls the renders until they are finished, spacing rounds out according to the
+// client's PollStrategy. Renders with a known status are first polled after one interval,
+// others immediately. Renders added to abandoned, if it isn't nil, are no longer polled.
+func (c *Client) watch(ctx context.Context, renders []Render, abandoned *renderSet) <-chan RenderEvent {
+	events := make(chan RenderEvent, len(renders))
+
+	go func() {
+		defer close(events)
+
+		pending := make([]Render, 0, len(renders))
+		for _, render := range renders {
+			if render.Status == "" || render.Status.InProgress() {
+				pending = append(pending, render)
+			}
+		}
+
+		// Renders with a known status were just fetched, so wait before the first round
+		round := 1
+		delay := time.Duration(0)
+		for _, render := range pending {
+			if render.Status != "" {
+				delay = orDefaultInterval(c.pollStrategy.Interval(round, pending))
+				round++
+				break
+			}
+		}
+
+		for len(pending) > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			pending = abandoned.without(pending)
+			if len(pending) == 0 {
+				return
+			}
+
+			// Poll the renders that waited longest first, and move them to the back
+			batchSize, roundEnds := c.pollBudget.take(len(pending), orDefaultInterval(c.pollStrategy.Interval(round, pending)))
+			if batchSize == 0 {
+				// Other watches used up the budget of this round
+				delay = time.Until(roundEnds)
+				continue
+			}
+			remaining := append([]Render(nil), pending[batchSize:]...)
+			for _, render := range pending[:batchSize] {
+				updated, event, keep := c.poll(ctx, render)
+				if event != nil {
+					select {
+					case events <- *event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if keep {
+					remaining = append(remaining, updated)
+				}
+			}
+			pending = remaining
+
+			delay = orDefaultInterval(c.pollStrategy.Interval(round, pending))
+			round++
+		}
+	}()
+
+	return events
+}
+
+// poll fetches a render. It returns the latest known state of the render, the event to emit
+// if any, and whether to keep polling the render.
+func (c *Client) poll(ctx context.Context, previous Render) (Render, *RenderEvent, bool) {
+	updated, err := c.FetchRender(ctx, previous.ID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return previous, nil, true
+		}
+		return previous, &RenderEvent{
+			Render:         previous,
+			PreviousStatus: previous.Status,
+			Err:            err,
+			Time:           time.Now(),
+		}, IsTemporary(err)
+	}
+
+	if updated.Status == previous.Status {
+		return *updated, nil, true
+	}
+	return *updated, &RenderEvent{
+		Render:         *updated,
+		PreviousStatus: previous.Status,
+		Time:           time.Now(),
+	}, updated.Status.InProgress()
+}
+
+// renderSet is a set of render IDs that is safe for concurrent use.
+type renderSet struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+func newRenderSet() *renderSet {
+	return &renderSet{ids: make(map[string]bool)}
+}
+
+func (s *renderSet) add(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[id] = true
+}
+
+// without returns the renders that aren't in the set. A nil set holds no renders.
+func (s *renderSet) without(renders []Render) []Render {
+	if s == nil {
+		return renders
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]Render, 0, len(renders))
+	for _, render := range renders {
+		if !s.ids[render.ID] {
+			result = append(result, render)
+		}
+	}
+	return result
+}
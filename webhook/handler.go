@@ -0,0 +1,305 @@
+// Package webhook receives the render callbacks that Creatomate posts to RenderOptions.WebhookURL.
+//
+//	http.Handle("/creatomate/webhook", &webhook.Handler{
+//	    OnSucceeded: func(ctx context.Context, render creatomate.Render) error {
+//	        return store.MarkDone(ctx, render.ID, render.URL)
+//	    },
+//	    OnFailed: func(ctx context.Context, render creatomate.Render) error {
+//	        return store.MarkFailed(ctx, render.ID, render.ErrorMessage)
+//	    },
+//	})
+//
+// When a callback returns an error, the handler responds with 500 so that the delivery is retried.
+//
+// To reject forged deliveries, create the client with creatomate.WithWebhookSecret and set the
+// same secret on the handler. The handler then verifies the signed metadata of every render,
+// rejects tokens older than MaxAge, and restores the original metadata before calling the
+// callbacks. Every render is dispatched once per token for its final status: deliveries that
+// were already processed are acknowledged without calling the callbacks again, while progress
+// deliveries are dispatched every time.
+//
+// The signature only covers the metadata, not the rest of the delivery, so without a Fetcher
+// a signed token can be replayed with another render ID or URL. Set Fetcher, e.g. to the
+// *creatomate.Client, to fetch every render from the API instead, so that the callbacks
+// receive the fetched render rather than the delivered one.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	creatomate "github.com/Lakeshore-Labs/creatomate-go"
+)
+
+const (
+	// DefaultMaxBodySize is the maximum payload size accepted when Handler.MaxBodySize is not set.
+	DefaultMaxBodySize = 1 << 20
+
+	// DefaultMaxAge is the maximum age of signed metadata when Handler.MaxAge is not set.
+	DefaultMaxAge = 24 * time.Hour
+)
+
+var (
+	// ErrStaleMetadata is returned when signed metadata is older than the handler's MaxAge.
+	ErrStaleMetadata = errors.New("signed metadata has expired")
+
+	// ErrRenderMismatch is returned when a delivered render doesn't match the render fetched
+	// from the API.
+	ErrRenderMismatch = errors.New("render doesn't match the signed metadata")
+)
+
+// RenderFetcher fetches a render from the API. It is implemented by *creatomate.Client.
+type RenderFetcher interface {
+	FetchRender(ctx context.Context, id string) (*creatomate.Render, error)
+}
+
+// Handler is an http.Handler that decodes render callbacks and dispatches them by status.
+type Handler struct {
+	// Called for every render that succeeded.
+	OnSucceeded func(ctx context.Context, render creatomate.Render) error
+
+	// Called for every render that failed.
+	OnFailed func(ctx context.Context, render creatomate.Render) error
+
+	// Called for renders that are still in progress. Such deliveries are ignored when nil.
+	OnProgress func(ctx context.Context, render creatomate.Render) error
+
+	// The maximum payload size in bytes. Defaults to DefaultMaxBodySize.
+	MaxBodySize int64
+
+	// The secret passed to creatomate.WithWebhookSecret. When set, deliveries without valid
+	// signed metadata are rejected.
+	Secret []byte
+
+	// The maximum age of signed metadata. Defaults to DefaultMaxAge.
+	MaxAge time.Duration
+
+	// Remembers processed renders, so that replayed deliveries are not dispatched again.
+	// Defaults to a MemoryNonceStore.
+	Nonces NonceStore
+
+	// Fetches signed renders from the API before they are dispatched. The fetched render must
+	// carry the delivered signed metadata, and replaces the delivered render.
+	Fetcher RenderFetcher
+
+	noncesOnce    sync.Once
+	defaultNonces NonceStore
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	maxBodySize := h.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	renders, err := Decode(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(h.Secret) == 0 {
+		for _, render := range renders {
+			if err := h.dispatch(r.Context(), render); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Renders are claimed by the nonce of their token and their ID, as one render start
+	// can produce multiple renders with the same token
+	keys := make([]string, len(renders))
+	for i, render := range renders {
+		signed, err := h.verify(render)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if h.Fetcher != nil {
+			fetched, err := h.Fetcher.FetchRender(r.Context(), render.ID)
+			if err != nil {
+				http.Error(w, "failed to fetch render "+render.ID, http.StatusInternalServerError)
+				return
+			}
+			if fetched.ID != render.ID || fetched.Metadata != render.Metadata {
+				http.Error(w, ErrRenderMismatch.Error(), http.StatusUnauthorized)
+				return
+			}
+			render = *fetched
+		}
+		render.Metadata = signed.Metadata
+		renders[i] = render
+		keys[i] = signed.Nonce + ":" + render.ID
+	}
+
+	for i, render := range renders {
+		if err := h.dispatchOnce(r.Context(), keys[i], render); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the signature and age of the render's metadata.
+func (h *Handler) verify(render creatomate.Render) (*creatomate.SignedMetadata, error) {
+	signed, err := creatomate.VerifyMetadata(h.Secret, render.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Since(signed.IssuedAt) > h.maxAge() {
+		return nil, ErrStaleMetadata
+	}
+	return signed, nil
+}
+
+// dispatchOnce dispatches a render with a final status unless its key was already claimed.
+// Renders that are still in progress are dispatched without claiming the key, so that the
+// delivery of the final status isn't mistaken for a replay.
+func (h *Handler) dispatchOnce(ctx context.Context, key string, render creatomate.Render) error {
+	if h.callback(render.Status) == nil {
+		return nil
+	}
+	if render.Status != creatomate.RenderStatusSucceeded && render.Status != creatomate.RenderStatusFailed {
+		return h.dispatch(ctx, render)
+	}
+
+	nonces := h.nonceStore()
+	claimed, err := nonces.Claim(ctx, key, time.Now().Add(h.maxAge()))
+	if err != nil {
+		return fmt.Errorf("failed to claim render %s: %w", render.ID, err)
+	}
+	if !claimed {
+		return nil
+	}
+
+	if err := h.dispatch(ctx, render); err != nil {
+		if releaseErr := nonces.Release(ctx, key); releaseErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to release render %s: %w", render.ID, releaseErr))
+		}
+		return err
+	}
+	return nil
+}
+
+func (h *Handler) maxAge() time.Duration {
+	if h.MaxAge > 0 {
+		return h.MaxAge
+	}
+	return DefaultMaxAge
+}
+
+func (h *Handler) nonceStore() NonceStore {
+	if h.Nonces != nil {
+		return h.Nonces
+	}
+	h.noncesOnce.Do(func() {
+		h.defaultNonces = NewMemoryNonceStore()
+	})
+	return h.defaultNonces
+}
+
+// dispatch calls the callback that matches the status of the render.
+func (h *Handler) dispatch(ctx context.Context, render creatomate.Render) error {
+	callback := h.callback(render.Status)
+	if callback == nil {
+		return nil
+	}
+	if err := callback(ctx, render); err != nil {
+		return fmt.Errorf("failed to process render %s: %w", render.ID, err)
+	}
+	return nil
+}
+
+// callback returns the callback for renders with the given status, or nil if there is none.
+func (h *Handler) callback(status creatomate.RenderStatus) func(context.Context, creatomate.Render) error {
+	switch status {
+	case creatomate.RenderStatusSucceeded:
+		return h.OnSucceeded
+	case creatomate.RenderStatusFailed:
+		return h.OnFailed
+	default:
+		return h.OnProgress
+	}
+}
+
+// Decode reads a webhook payload, which holds either a single render or an array of renders,
+// and validates the required fields of every render.
+func Decode(r io.Reader) ([]creatomate.Render, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errors.New("empty payload")
+	}
+
+	var renders []creatomate.Render
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &renders); err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+	} else {
+		var render creatomate.Render
+		if err := json.Unmarshal(body, &render); err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+		renders = append(renders, render)
+	}
+
+	for i, render := range renders {
+		if err := validate(render); err != nil {
+			return nil, fmt.Errorf("invalid render at index %d: %w", i, err)
+		}
+	}
+	return renders, nil
+}
+
+// validate checks the fields that every callback relies on.
+func validate(render creatomate.Render) error {
+	if render.ID == "" {
+		return errors.New("missing id")
+	}
+
+	switch render.Status {
+	case creatomate.RenderStatusSucceeded:
+		if render.URL == "" {
+			return errors.New("missing url")
+		}
+	case creatomate.RenderStatusFailed,
+		creatomate.RenderStatusPlanned,
+		creatomate.RenderStatusWaiting,
+		creatomate.RenderStatusTranscribing,
+		creatomate.RenderStatusRendering:
+	case "":
+		return errors.New("missing status")
+	default:
+		return fmt.Errorf("unknown status %q", render.Status)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NonceStore remembers which deliveries have already been processed.
+type NonceStore interface {
+	// Claim records key until expiresAt. It returns false if key is already recorded.
+	Claim(ctx context.Context, key string, expiresAt time.Time) (bool, error)
+
+	// Release forgets key, so that a delivery that failed to process can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryNonceStore is a NonceStore that keeps keys in memory. It is only suitable when
+// webhooks are received by a single process.
+type MemoryNonceStore struct {
+	mu   sync.Mutex
+	keys map[string]time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{keys: make(map[string]time.Time)}
+}
+
+func (s *MemoryNonceStore) Claim(ctx context.Context, key string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, expiry := range s.keys {
+		if !expiry.After(now) {
+			delete(s.keys, k)
+		}
+	}
+
+	if _, ok := s.keys[key]; ok {
+		return false, nil
+	}
+	s.keys[key] = expiresAt
+	return true, nil
+}
+
+func (s *MemoryNonceStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}